@@ -0,0 +1,77 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+)
+
+const (
+	// editorconfig-checker-disable
+	alarmExample = `
+  # Arm the alarm panel when leaving, alarm panels are never matched fuzzy
+  hctl alarm arm-away home_alarm --code 1234
+
+  # Arm the alarm panel for the night at home
+  hctl alarm arm-home home_alarm --code 1234
+
+  # Disarm the alarm panel
+  hctl alarm disarm home_alarm --code 1234
+  `
+	// editorconfig-checker-enable
+)
+
+func newAlarmCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "alarm",
+		Short:   "Arm and disarm alarm panels",
+		Example: alarmExample,
+	}
+
+	cmd.AddCommand(
+		newAlarmActionCmd(h, out, "arm-away", "Arm alarm panels in away mode", "alarm_arm_away", "armed away", "disarmed"),
+		newAlarmActionCmd(h, out, "arm-home", "Arm alarm panels in home mode", "alarm_arm_home", "armed home", "disarmed"),
+		newAlarmActionCmd(h, out, "disarm", "Disarm alarm panels", "alarm_disarm", "disarmed", ""),
+	)
+
+	return cmd
+}
+
+// newAlarmActionCmd creates a subcommand calling service, state is the state of panels offered in completion
+func newAlarmActionCmd(h *pkg.Hctl, out io.Writer, use string, short string, service string, done string, state string) *cobra.Command {
+	var code string
+	a := serviceAction{domains: []string{"alarm_control_panel"}, service: service, verb: use, done: done}
+
+	cmd := &cobra.Command{
+		Use:   use + " PANEL... [--code CODE] [-y|--yes]",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, state, h, a.domains...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			a.data = codeData(code)
+			a.run(h, out, args)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&code, "code", "", "Code of the alarm panel, if required")
+
+	return cmd
+}
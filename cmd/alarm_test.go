@@ -0,0 +1,51 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_alarm(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 arm away": {
+			"alarm arm-away home_alarm --code 1234 -y",
+			`Home alarm \(home_alarm\) armed away`,
+			"",
+		},
+		"2 arm home": {
+			"alarm arm-home alarm_control_panel.home_alarm --yes",
+			`Home alarm \(home_alarm\) armed home`,
+			"",
+		},
+		"3 disarm": {
+			"alarm disarm home_alarm --code 1234 --yes",
+			`Home alarm \(home_alarm\) disarmed`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+)
+
+func newAutomationCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "automation",
+		Short:   "Trigger, enable, disable and list automations",
+		Aliases: []string{"auto"},
+		Example: automationTriggerExample + automationEnableExample + automationListExample,
+	}
+
+	cmd.AddCommand(
+		newAutomationTriggerCmd(h, out),
+		newAutomationEnableCmd(h, out, true),
+		newAutomationEnableCmd(h, out, false),
+		newAutomationListCmd(h, out),
+	)
+
+	return cmd
+}
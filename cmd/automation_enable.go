@@ -0,0 +1,71 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	automationEnableExample = `
+  # Disable automations while you are working on the garage, and enable them again
+  hctl automation disable garage_door_reminder garage_light
+  hctl automation enable garage_door_reminder garage_light
+  `
+	// editorconfig-checker-enable
+)
+
+// newAutomationEnableCmd creates the enable or (enable=false) the disable command
+func newAutomationEnableCmd(h *pkg.Hctl, out io.Writer, enable bool) *cobra.Command {
+	use, short, state := "enable", "Enable automations", "off"
+	if !enable {
+		use, short, state = "disable", "Disable automations", "on"
+	}
+
+	cmd := &cobra.Command{
+		Use:     use + " AUTOMATION...",
+		Short:   short,
+		Example: automationEnableExample,
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			// only offer automations that would change
+			return compListStatesMulti(toComplete, args, nil, nil, state, h, "automation")
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			var hasErr bool
+			for _, a := range args {
+				entityID, results, err := h.EnableAutomation(a, enable)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+					continue
+				}
+				o.FprintResult(out, entityID, use+"d", results)
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	automationListExample = `
+  # List all automations with the time they were last triggered
+  hctl automation list
+  `
+	// editorconfig-checker-enable
+)
+
+func newAutomationListCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List automations and when they were last triggered",
+		Aliases: []string{"l", "ls"},
+		Example: automationListExample,
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			automations, err := h.GetAutomations()
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			var rows [][]any
+			for _, a := range automations {
+				name, _ := a.Attributes["friendly_name"].(string)
+				mode, _ := a.Attributes["mode"].(string)
+				// keep null for automations that never ran in structured output
+				lastTriggered := a.Attributes["last_triggered"]
+				if !o.Structured() {
+					ts, _ := lastTriggered.(string)
+					lastTriggered = formatTimestamp(ts)
+				}
+				rows = append(rows, []any{a.EntityID, name, a.State, lastTriggered, mode})
+			}
+			o.FprintSuccessListWithHeader(out, []any{"AUTOMATION", "NAME", "STATE", "LAST TRIGGERED", "MODE"}, rows)
+		},
+	}
+
+	return cmd
+}
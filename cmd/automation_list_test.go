@@ -0,0 +1,48 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_automationList(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		"list": {
+			"automation list",
+			`^AUTOMATION\s+NAME\s+STATE\s+LAST TRIGGERED\s+MODE\s*\n` +
+				`automation.some_automation\s+Some automation\s+on\s+2024-10-0\d \d\d:41:36\s+single\s*\n` +
+				`automation.garage_door_reminder\s+Garage door reminder\s+off\s+-\s+single\s*\n$`,
+			"",
+		},
+		"json": {
+			"automation ls -o json",
+			`(?m)^\{"automation":"automation.some_automation","name":"Some automation","state":"on","last_triggered":"2024-10-01T15:41:36.363764\+00:00","mode":"single"\}\n` +
+				`\{"automation":"automation.garage_door_reminder","name":"Garage door reminder","state":"off","last_triggered":null,"mode":"single"\}\n$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
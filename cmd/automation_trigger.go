@@ -0,0 +1,71 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	automationTriggerExample = `
+  # Run the actions of an automation, if its conditions are met
+  hctl automation trigger garage_door_reminder
+
+  # Run the actions of an automation, skipping its conditions
+  hctl automation trigger garage_door_reminder --skip-condition
+  `
+	// editorconfig-checker-enable
+)
+
+func newAutomationTriggerCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var skipCondition bool
+
+	cmd := &cobra.Command{
+		Use:     "trigger AUTOMATION...",
+		Short:   "Trigger automations",
+		Aliases: []string{"t"},
+		Example: automationTriggerExample,
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h, "automation")
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			var hasErr bool
+			for _, a := range args {
+				entityID, results, err := h.TriggerAutomation(a, skipCondition)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+					continue
+				}
+				o.FprintResult(out, entityID, "triggered", results)
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&skipCondition, "skip-condition", false, "Run the actions even if the conditions are not met")
+
+	return cmd
+}
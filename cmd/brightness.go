@@ -19,6 +19,7 @@ import (
 	"io"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -33,8 +34,10 @@ var (
 )
 
 func newBrightnessCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var wait time.Duration
+
 	cmd := &cobra.Command{
-		Use:     "brightness [+|-|min|max|1-99]",
+		Use:     "brightness [+|-|min|max|1-99] [--wait[=timeout]]",
 		Short:   "Change brightness",
 		Aliases: []string{"b", "br", "bright"},
 		Args:    cobra.MatchAll(cobra.MinimumNArgs(2)),
@@ -56,17 +59,20 @@ func newBrightnessCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 			return nil
 		},
 		Run: func(_ *cobra.Command, args []string) {
-			c := h.GetRest()
+			c := h.GetBackend()
 			value := args[len(args)-1]
 			devices := args[:len(args)-1]
 			var hasErr bool
 			for _, device := range devices {
-				obj, state, sub, err := c.TurnLightOnCustom(device, value, "", 0, 0)
+				obj, state, sub, results, err := c.TurnLightOnCustom(device, value, "", 0, 0)
+				if err == nil {
+					results, err = waitForTarget(c, wait, results)
+				}
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccess(out, fmt.Sprintf("%s brightness set to %s%%", obj, value))
+					o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), fmt.Sprintf("brightness set to %s%%", value), results)
 				}
 				log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 			}
@@ -76,6 +82,8 @@ func newBrightnessCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 		},
 	}
 
+	addWaitFlag(cmd, &wait)
+
 	return cmd
 }
 
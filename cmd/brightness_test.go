@@ -30,42 +30,42 @@ func Test_newCmdBrightness(t *testing.T) {
 	var tests = map[string]cmdTest{
 		"set brightness": {
 			"brightness light.livingroom_other 20",
-			"(?m)^.*livingroom_other brightness set to 20%",
+			`(?m)^.*livingroom_other\) brightness set to 20%`,
 			"",
 		},
 		"max brightness": {
-			"brightness light.livingroom_other max",
-			"(?m)^.*livingroom_other brightness set to max%",
+			"brightness light.livingroom_corner max",
+			`(?m)^.*livingroom_corner\) brightness set to max%`,
 			"",
 		},
 		"min brightness": {
-			"brightness light.livingroom_other min",
-			"(?m)^.*livingroom_other brightness set to min%",
+			"brightness light.bedroom_main min",
+			`(?m)^.*bedroom_main\) brightness set to min%`,
 			"",
 		},
 		"increase brightness (offline)": {
-			"brightness light.livingroom_other +",
-			`(?m)^.*livingroom_other brightness set to \+%`,
+			"brightness light.livingroom_main +",
+			`(?m)^.*livingroom_main\) brightness set to \+%`,
 			"",
 		},
 		"decrease brightness (offline)": {
 			"brightness light.livingroom_other -",
-			"(?m)^.*livingroom_other brightness set to -%",
+			`(?m)^.*livingroom_other\) brightness set to -%`,
 			"",
 		},
 		"increase brightness": {
 			"brightness light.bedroom_other +",
-			`(?m)^.*bedroom_other brightness set to \+%`,
+			`(?m)^.*bedroom_other\) brightness set to \+%`,
 			"",
 		},
 		"decrease brightness": {
 			"brightness light.bedroom_other -",
-			"(?m)^.*bedroom_other brightness set to -%",
+			`(?m)^.*bedroom_other\) brightness set to -%`,
 			"",
 		},
 		"set brightness multiple": {
-			"brightness light.livingroom_other light.bedroom_other 20",
-			"(?s).*livingroom_other brightness set to 20%.*bedroom_other brightness set to 20%",
+			"brightness light.livingroom_other light.bedroom_other 30",
+			`(?s).*livingroom_other\) brightness set to 30%.*bedroom_other\) brightness set to 30%`,
 			"",
 		},
 	}
@@ -0,0 +1,83 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	cacheExample = `
+  # Update cached states and services used for completion
+  hctl cache refresh
+
+  # Remove all cached data
+  hctl cache clear
+
+  # Change how long the cache is considered fresh (0 disables the cache)
+  hctl config set handling.cache_ttl 1h
+  `
+	// editorconfig-checker-enable
+)
+
+func newCacheCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cache",
+		Short:   "Manage the completion cache",
+		Example: cacheExample,
+	}
+
+	var locked bool
+	refresh := &cobra.Command{
+		Use:   "refresh",
+		Short: "Refresh cached states and services",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := h.RefreshCache(locked); err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintSuccess(out, "Cache refreshed")
+		},
+	}
+	// set by the background refresh, which hands over the lock it already holds
+	refresh.Flags().BoolVar(&locked, "locked", false, "Refresh lock is already held by the caller")
+	if err := refresh.Flags().MarkHidden("locked"); err != nil {
+		log.Error().Msgf("Could not hide flag locked: %+v", err)
+	}
+
+	cmd.AddCommand(
+		refresh,
+		&cobra.Command{
+			Use:   "clear",
+			Short: "Remove all cached data",
+			Args:  cobra.NoArgs,
+			Run: func(_ *cobra.Command, _ []string) {
+				if err := h.ClearCache(); err != nil {
+					o.FprintError(out, err)
+				}
+				o.FprintSuccess(out, "Cache cleared")
+			},
+		},
+	)
+
+	return cmd
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg"
+	"github.com/xx4h/hctl/pkg/cache"
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_newCmdCache(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"refresh": {
+			"cache refresh",
+			"(?m)^.*Cache refreshed",
+			"",
+		},
+		"complete from cache": {
+			"__complete toggle bedroom_m",
+			"(?m)^bedroom_main$",
+			"",
+		},
+		"clear": {
+			"cache clear",
+			"(?m)^.*Cache cleared",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_cacheRefreshLock(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+	dir, err := cache.DefaultDir(ms.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cache.New(dir, 0)
+	defer c.Clear()
+
+	// another process is refreshing, its lock must be left alone
+	if !c.TryLock() {
+		t.Fatal("could not take lock")
+	}
+	if err := h.RefreshCache(false); err == nil {
+		t.Error("expected error while another refresh holds the lock")
+	}
+	if c.TryLock() {
+		t.Error("lock of the other refresh has been removed")
+	}
+
+	// a background refresh owns the lock handed over and releases it
+	testCmd(t, h, map[string]cmdTest{
+		"refresh locked": {"cache refresh --locked", "(?m)^.*Cache refreshed", ""},
+	})
+	if !c.TryLock() {
+		t.Error("lock has not been released")
+	}
+	c.Unlock()
+}
+
+func Test_cachedBackendMiss(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+	if err := h.ClearCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := h.GetCachedBackend()
+	// states fetched on the miss are served without asking the hub again
+	ms.Close()
+	states, err := b.GetStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) == 0 {
+		t.Error("got no states")
+	}
+}
+
+func Test_configFileEnv(t *testing.T) {
+	// the background cache refresh uses the config file of its parent
+	file := path.Join(t.TempDir(), "other.yaml")
+	if err := os.WriteFile(file, []byte("hub:\n  url: http://other.example/api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HCTL_CONFIG", file)
+	h, err := pkg.NewHctl(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := h.GetConfigValue("hub.url"); err != nil || v != "http://other.example/api" {
+		t.Errorf("got hub.url %v (%v), want the one of %s", v, err, file)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	calendarAddExample = `
+  # Add an all-day event
+  hctl calendar add household "Bin collection: glass" --start 2024-10-24
+
+  # Add an event lasting several days
+  hctl calendar add household Vacation --start 2024-12-23 --end 2024-12-27
+
+  # Add an event with time, ending an hour later (default)
+  hctl calendar add household Dentist --start "2024-10-11 09:30" --location "Main Street 12"
+
+  # Add an event starting in two hours, lasting 30 minutes
+  hctl calendar add household "Call grandma" --start 2h --end 150m
+  `
+	// editorconfig-checker-enable
+)
+
+func newCalendarAddCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var event pkg.NewCalendarEvent
+
+	cmd := &cobra.Command{
+		Use:     "add CALENDAR SUMMARY",
+		Short:   "Add an event to a calendar",
+		Aliases: []string{"a"},
+		Example: calendarAddExample,
+		Args:    cobra.ExactArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return compListStates(toComplete, args, nil, nil, "", h, "calendar")
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			if event.Start == "" {
+				o.FprintError(out, fmt.Errorf("--start is required"))
+			}
+			event.Summary = args[1]
+			calendar, err := h.AddCalendarEvent(args[0], event, time.Now())
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintSuccess(out, fmt.Sprintf("Added %s to %s", event.Summary, calendar))
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&event.Start, "start", "", "Start, a date for all-day events or a timestamp or duration ahead (e.g. 2h)")
+	cmd.PersistentFlags().StringVar(&event.End, "end", "", "End, the last day of all-day events (default start) or a timestamp or duration ahead (default an hour after start)")
+	cmd.PersistentFlags().StringVar(&event.Description, "description", "", "Description of the event")
+	cmd.PersistentFlags().StringVar(&event.Location, "location", "", "Location of the event")
+
+	return cmd
+}
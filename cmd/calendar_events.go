@@ -0,0 +1,134 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	// editorconfig-checker-disable
+	calendarEventsExample = `
+  # Show the events of all calendars for the next 7 days
+  hctl calendar events
+
+  # Show the events since yesterday
+  hctl calendar events --from -1d
+
+  # Show the events of a single calendar for the next month
+  hctl calendar events household --to 30d
+
+  # Show all events of October as JSON lines
+  hctl calendar events --from 2024-10-01 --to 2024-11-01 -o json
+  `
+	// editorconfig-checker-enable
+)
+
+func newCalendarEventsCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:     "events [CALENDAR...]",
+		Short:   "Show upcoming events of calendars",
+		Aliases: []string{"e", "ev"},
+		Example: calendarEventsExample,
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h, "calendar")
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			now := time.Now()
+			start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+			var err error
+			if from != "" {
+				if start, err = util.ParseFutureTime(from, now); err != nil {
+					o.FprintError(out, err)
+				}
+			}
+			end := start.AddDate(0, 0, 7)
+			if to != "" {
+				if end, err = util.ParseFutureTime(to, now); err != nil {
+					o.FprintError(out, err)
+				}
+			}
+			if !start.Before(end) {
+				o.FprintError(out, fmt.Errorf("--from must be before --to"))
+			}
+
+			events, err := h.GetCalendarEvents(args, start, end)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			printCalendarEvents(out, events)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&from, "from", "", "Start of the period, a duration ahead (- for before now) or a timestamp (default today)")
+	cmd.PersistentFlags().StringVar(&to, "to", "", "End of the period, a duration ahead (e.g. 30d) or a timestamp (default 7 days after --from)")
+
+	return cmd
+}
+
+func printCalendarEvents(out io.Writer, events []rest.CalendarEvent) {
+	if o.Structured() {
+		for _, e := range events {
+			if err := o.FprintRecord(out, e); err != nil {
+				o.FprintError(out, err)
+			}
+		}
+		return
+	}
+	if len(events) == 0 {
+		o.FprintWarning(out, "No events in this period")
+		return
+	}
+	var rows [][]any
+	for _, e := range events {
+		rows = append(rows, []any{e.Calendar, formatEventTime(e), e.Summary, e.Location})
+	}
+	o.FprintSuccessListWithHeader(out, []any{"CALENDAR", "WHEN", "SUMMARY", "LOCATION"}, rows)
+}
+
+// formatEventTime returns the period of an event in local time, e.g. "2024-10-11 09:30-10:15"
+// or "2024-10-14 - 2024-10-19 (all day)"
+func formatEventTime(e rest.CalendarEvent) string {
+	start, errStart := e.Start.Time()
+	end, errEnd := e.End.Time()
+	if errStart != nil || errEnd != nil {
+		return fmt.Sprintf("%s%s - %s%s", e.Start.Date, e.Start.DateTime, e.End.Date, e.End.DateTime)
+	}
+	start, end = start.Local(), end.Local()
+
+	if e.AllDay() {
+		// the end of all-day events is exclusive
+		last := end.AddDate(0, 0, -1)
+		if !last.After(start) {
+			return fmt.Sprintf("%s (all day)", start.Format(time.DateOnly))
+		}
+		return fmt.Sprintf("%s - %s (all day)", start.Format(time.DateOnly), last.Format(time.DateOnly))
+	}
+	if start.Format(time.DateOnly) == end.Format(time.DateOnly) {
+		return fmt.Sprintf("%s-%s", start.Format("2006-01-02 15:04"), end.Format("15:04"))
+	}
+	return fmt.Sprintf("%s - %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_calendarEvents(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		"week": {
+			"calendar events --from 2024-10-09",
+			`^CALENDAR\s+WHEN\s+SUMMARY\s+LOCATION\s*\n` +
+				`calendar.household\s+2024-10-10 \(all day\)\s+Bin collection: paper\s*\n` +
+				// times are shown in local time
+				`calendar.household\s+2024-10-1\d \d\d:30-\d\d:15\s+Dentist\s+Main Street 12\s*\n` +
+				`calendar.household\s+2024-10-14 - 2024-10-19 \(all day\)\s+Vacation\s*\n$`,
+			"",
+		},
+		"single": {
+			"calendar ev holidays --from 2024-10-09 --to +30d",
+			`^CALENDAR\s+WHEN\s+SUMMARY\s+LOCATION\s*\n` +
+				`calendar.holidays\s+2024-10-31 \(all day\)\s+Halloween\s*\n$`,
+			"",
+		},
+		"json": {
+			"calendar events household --from 2024-10-11 --to 2024-10-12 -o json",
+			`^\{"calendar":"calendar.household","summary":"Dentist","start":\{"dateTime":"2024-10-11T09:30:00\+00:00"\},` +
+				`"end":\{"dateTime":"2024-10-11T10:15:00\+00:00"\},"location":"Main Street 12","uid":"dentist-20241011"\}\n$`,
+			"",
+		},
+		"none": {
+			"calendar events --from 2024-09-01 --to 2024-09-30",
+			"No events in this period",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
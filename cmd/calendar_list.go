@@ -0,0 +1,55 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	calendarListExample = `
+  # List all calendars
+  hctl calendar list
+  `
+	// editorconfig-checker-enable
+)
+
+func newCalendarListCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List calendars",
+		Aliases: []string{"l", "ls"},
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			calendars, err := h.GetCalendars()
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			var rows [][]any
+			for _, c := range calendars {
+				rows = append(rows, []any{c.EntityID, c.Name})
+			}
+			o.FprintSuccessListWithHeader(out, []any{"CALENDAR", "NAME"}, rows)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	callExample = `
+  # Call a service for an entity (short names, device_map and fuzzy matching work as usual)
+  hctl call light.turn_on bedroom_main --data brightness=120 --data rgb_color=[255,0,0]
+
+  # Call a service for multiple entities, passing data as JSON
+  hctl call media_player.volume_set player1 player2 --json '{"volume_level": 0.3}'
+
+  # Call a service without entities
+  hctl call homeassistant.reload_core_config
+  `
+	// editorconfig-checker-enable
+)
+
+func newCallCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var data []string
+	var jsonData string
+
+	cmd := &cobra.Command{
+		Use:     "call DOMAIN.SERVICE [ENTITY...] [--data key=value]... [--json '{...}']",
+		Short:   "Call any service",
+		Example: callExample,
+		Args:    cobra.MatchAll(cobra.MinimumNArgs(1)),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return compListServices(toComplete, h)
+			}
+			domain, service, _ := strings.Cut(args[0], ".")
+			return compListStatesMulti(toComplete, args[1:], []string{service}, nil, "", h, callDomains(domain, h)...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			domain, service, ok := strings.Cut(args[0], ".")
+			if !ok || domain == "" || service == "" {
+				o.FprintError(out, fmt.Errorf("service needs to be in format DOMAIN.SERVICE: %s", args[0]))
+			}
+			payload, err := parseServiceData(data, jsonData)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+
+			results, err := h.GetBackend().CallService(domain, service, args[1:], payload)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			log.Debug().Caller().Msgf("Result: %s.%s with %v", domain, service, payload)
+
+			if len(results) == 0 {
+				o.FprintSuccess(out, fmt.Sprintf("Called %s.%s", domain, service))
+				return
+			}
+			o.FprintResults(out, args[0], results)
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVarP(&data, "data", "d", []string{}, "Service data as key=value, values are parsed as JSON if possible (e.g. brightness=120)")
+	cmd.PersistentFlags().StringVar(&jsonData, "json", "", "Service data as JSON object, --data takes precedence")
+
+	return cmd
+}
+
+// callDomains returns the domains entities of a service of domain are completed from
+// Like in CallService, these are the entities of domain, or all entities if the
+// domain has none (e.g. homeassistant.turn_on)
+func callDomains(domain string, h *pkg.Hctl) []string {
+	states, err := h.GetCachedBackend().GetFilteredStates([]string{domain})
+	if err != nil || len(states) == 0 {
+		return nil
+	}
+	return []string{domain}
+}
+
+// service data keys selecting entities, these are only taken from the arguments
+// so calls for protected entities can't skip the confirmation
+var targetKeys = []string{"entity_id", "area_id", "device_id", "target"}
+
+// parseServiceData is parseData, but entities have to be passed as arguments
+func parseServiceData(data []string, jsonData string) (map[string]any, error) {
+	payload, err := parseData(data, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range targetKeys {
+		if _, ok := payload[k]; ok {
+			return nil, fmt.Errorf("pass entities as arguments instead of %s in --data or --json", k)
+		}
+	}
+	return payload, nil
+}
+
+// parseData merges the JSON object of --json with all key=value pairs of --data
+func parseData(data []string, jsonData string) (map[string]any, error) {
+	payload := map[string]any{}
+	if jsonData != "" {
+		if err := json.Unmarshal([]byte(jsonData), &payload); err != nil {
+			return nil, fmt.Errorf("--json needs to be a JSON object: %v", err)
+		}
+	}
+	for _, d := range data {
+		k, v, ok := strings.Cut(d, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("--data needs to be in format key=value: %s", d)
+		}
+		var val any
+		if err := json.Unmarshal([]byte(v), &val); err != nil {
+			// not JSON, so it is a plain string
+			val = v
+		}
+		payload[k] = val
+	}
+	return payload, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_newCmdCall(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"call service for entity": {
+			"call light.turn_on livingroom_main --data brightness=100",
+			`(?m)^.*Living Main \(livingroom_main\) on`,
+			"",
+		},
+		"call service for multiple entities": {
+			"call switch.toggle switch.livingroom_warp bedroom_warp",
+			`(?s).*livingroom_warp\) on.*bedroom_warp\) on`,
+			"",
+		},
+		"call service with json data": {
+			"call climate.set_temperature heating --json {\"temperature\":23.5}",
+			`(?m)^.*Heating \(heating\) heat`,
+			"",
+		},
+		"call service for protected entity with yes": {
+			"call lock.unlock front_door --yes",
+			`Front door \(front_door\) unlocked`,
+			"",
+		},
+		"call service without entity": {
+			"call homeassistant.reload_core_config",
+			`(?m)^.*Called homeassistant.reload_core_config`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_newCmdCallProtected(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	// lock.front_door is protected, so calling any service for it asks
+	args := []string{"call", "lock.unlock", "front_door"}
+	out := new(bytes.Buffer)
+	errout := new(bytes.Buffer)
+	rootCmd = newRootCmd(h, out, args)
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errout)
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil {
+		t.Error(err)
+	}
+	if got, want := errout.String(), "lock.front_door is protected, really call lock.unlock? [y/N] "; got != want {
+		t.Errorf("got prompt %q, want %q", got, want)
+	}
+	if got := out.String(); !strings.Contains(got, "(front_door) unlocked") {
+		t.Errorf("got %s, want front_door unlocked", got)
+	}
+}
+
+func Test_parseServiceData(t *testing.T) {
+	tests := map[string]struct {
+		data     []string
+		jsonData string
+		want     map[string]any
+		wantErr  bool
+	}{
+		"typed values": {
+			data: []string{"brightness=120", "rgb_color=[255,0,0]", "flash=true", "effect=colorloop"},
+			want: map[string]any{"brightness": float64(120), "rgb_color": []any{float64(255), float64(0), float64(0)}, "flash": true, "effect": "colorloop"},
+		},
+		"data overrides json": {
+			data:     []string{"volume_level=0.5"},
+			jsonData: `{"volume_level": 0.3, "media": "x"}`,
+			want:     map[string]any{"volume_level": 0.5, "media": "x"},
+		},
+		"value with equal sign": {
+			data: []string{"message=a=b"},
+			want: map[string]any{"message": "a=b"},
+		},
+		"missing value":    {data: []string{"brightness"}, wantErr: true},
+		"invalid json":     {jsonData: `[1]`, wantErr: true},
+		"entity in data":   {data: []string{"entity_id=light.x"}, wantErr: true},
+		"entities in json": {jsonData: `{"entity_id": ["lock.front_door"]}`, wantErr: true},
+		"area in json":     {jsonData: `{"area_id": "hallway"}`, wantErr: true},
+		"device in json":   {jsonData: `{"device_id": ["abc123"]}`, wantErr: true},
+		"target in json":   {jsonData: `{"target": {"entity_id": "lock.front_door"}}`, wantErr: true},
+		"area in data":     {data: []string{"area_id=hallway"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseServiceData(tt.data, tt.jsonData)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newCmdCallCompletion(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]struct {
+		service string
+		want    string
+		notWant string
+	}{
+		"only entities of the domain": {"light.turn_on", "livingroom_main", "livingroom_warp"},
+		"all entities":                {"homeassistant.turn_on", "livingroom_warp", ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			args := []string{"__complete", "call", tt.service, ""}
+			out := new(bytes.Buffer)
+			rootCmd = newRootCmd(h, out, args)
+			rootCmd.SetOut(out)
+			rootCmd.SetArgs(args)
+			if err := rootCmd.Execute(); err != nil {
+				t.Error(err)
+			}
+			choices := strings.Split(out.String(), "\n")
+			if !slices.Contains(choices, tt.want) {
+				t.Errorf("got %v, want %s", choices, tt.want)
+			}
+			if tt.notWant != "" && slices.Contains(choices, tt.notWant) {
+				t.Errorf("got %v, want no %s", choices, tt.notWant)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/xx4h/hctl/pkg"
+	"github.com/xx4h/hctl/pkg/rest"
 )
 
 func newCompletionCmd() *cobra.Command {
@@ -117,18 +118,20 @@ func noMoreArgsComp() ([]string, cobra.ShellCompDirective) {
 	return cobra.AppendActiveHelp(nil, activeHelpMsg), cobra.ShellCompDirectiveNoFileComp
 }
 
-// support function for completion
-func compListStates(_ string, ignoredStates []string, serviceCaps []string, attributes []string, state string, h *pkg.Hctl) ([]string, cobra.ShellCompDirective) {
-	states, err := h.GetStates()
+// support function for completion, if domains are given only entities of these domains are listed
+func compListStates(_ string, ignoredStates []string, serviceCaps []string, attributes []string, state string, h *pkg.Hctl, domains ...string) ([]string, cobra.ShellCompDirective) {
+	c := h.GetCachedBackend()
+	states, err := c.GetStates()
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
 	}
-	services, err := h.GetServices()
+	services, err := c.GetServices()
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
 	}
 
 	filteredStates := filterStates(states, ignoredStates)
+	filteredStates = filterDomains(filteredStates, domains)
 	filteredStates = filterCapable(filteredStates, services, serviceCaps, state)
 	filteredStates = filterAttributes(filteredStates, attributes)
 
@@ -154,9 +157,72 @@ func compListStates(_ string, ignoredStates []string, serviceCaps []string, attr
 	return choices, cobra.ShellCompDirectiveNoFileComp
 }
 
+// compListServices returns all services as domain.service
+func compListServices(_ string, h *pkg.Hctl) ([]string, cobra.ShellCompDirective) {
+	services, err := h.GetCachedBackend().GetServices()
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+	var choices []string
+	for _, d := range services {
+		for svc := range d.Services {
+			choices = append(choices, fmt.Sprintf("%s.%s", d.Domain, svc))
+		}
+	}
+	slices.Sort(choices)
+	return choices, cobra.ShellCompDirectiveNoFileComp
+}
+
+// compListEvents returns all event types with listeners
+func compListEvents(_ string, h *pkg.Hctl) ([]string, cobra.ShellCompDirective) {
+	events, err := h.GetEvents()
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+	var choices []string
+	for _, e := range events {
+		choices = append(choices, e.Event)
+	}
+	return choices, cobra.ShellCompDirectiveNoFileComp
+}
+
+// compListPresetModes returns the preset modes of the fans in args, or of all fans if args holds none
+func compListPresetModes(args []string, h *pkg.Hctl) []string {
+	states, err := h.GetCachedBackend().GetFilteredStates([]string{"fan"})
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+	deviceMap := h.GetMap("device_map")
+	selected := func(entityID string) bool {
+		_, name, _ := strings.Cut(entityID, ".")
+		for _, a := range args {
+			if a == entityID || a == name || deviceMap[a] == entityID {
+				return true
+			}
+		}
+		return false
+	}
+	if !slices.ContainsFunc(states, func(s rest.HassState) bool { return selected(s.EntityID) }) {
+		selected = func(string) bool { return true }
+	}
+
+	var choices []string
+	for _, s := range states {
+		if !selected(s.EntityID) {
+			continue
+		}
+		for _, m := range rest.FanPresetModes(s) {
+			if !slices.Contains(choices, m) {
+				choices = append(choices, m)
+			}
+		}
+	}
+	return choices
+}
+
 // compListStatesMulti wraps compListStates and filters out already-selected devices
-func compListStatesMulti(toComplete string, args []string, serviceCaps []string, attributes []string, state string, h *pkg.Hctl) ([]string, cobra.ShellCompDirective) {
-	choices, directive := compListStates(toComplete, nil, serviceCaps, attributes, state, h)
+func compListStatesMulti(toComplete string, args []string, serviceCaps []string, attributes []string, state string, h *pkg.Hctl, domains ...string) ([]string, cobra.ShellCompDirective) {
+	choices, directive := compListStates(toComplete, nil, serviceCaps, attributes, state, h, domains...)
 	if len(args) > 0 {
 		var filtered []string
 		for _, c := range choices {
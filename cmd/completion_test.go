@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/xx4h/hctl/pkg/hctltest"
@@ -51,21 +52,21 @@ func Test_compListStates(t *testing.T) {
 			[]string{"turn_on"},
 			nil,
 			"",
-			12,
+			17,
 		},
 		"serviceCap turn_on + state off": {
 			nil,
 			[]string{"turn_on"},
 			nil,
 			"off",
-			5,
+			8,
 		},
 		"serviceCap turn_off + state on": {
 			nil,
 			[]string{"turn_off"},
 			nil,
 			"on",
-			7,
+			8,
 		},
 		"serviceCap play_media + attrib device_class": {
 			nil,
@@ -100,3 +101,48 @@ func Test_compListStates(t *testing.T) {
 		})
 	}
 }
+
+func Test_compListStatesDomains(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Errorf("Could not set hub.url to %s: %+v", ms.URL, err)
+	}
+	defer ms.Close()
+
+	s, _ := compListStates("", nil, nil, nil, "", h, "camera", "image")
+	// device_map entries are always offered
+	want := []string{"a", "front_door", "doorbell"}
+	if !slices.Equal(s, want) {
+		t.Errorf("got %v, want %v", s, want)
+	}
+}
+
+func Test_compListPresetModes(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Errorf("Could not set hub.url to %s: %+v", ms.URL, err)
+	}
+	defer ms.Close()
+
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"fan":         {[]string{"bedroom_fan"}, []string{"auto", "sleep", "nature"}},
+		"no presets":  {[]string{"fan.attic_fan"}, nil},
+		"no fan yet":  {nil, []string{"auto", "sleep", "nature"}},
+		"unknown fan": {[]string{"kitchen_fan"}, []string{"auto", "sleep", "nature"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := compListPresetModes(tt.args, h); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
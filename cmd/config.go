@@ -28,13 +28,15 @@ func newConfigCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 		Use:     "config",
 		Short:   "Set and get config",
 		Aliases: []string{"c", "co", "con", "conf"},
-		Example: configGetExample + configSetExample,
+		Example: configGetExample + configSetExample + configContextExample,
 	}
 
 	cmd.AddCommand(
 		newConfigGetCmd(h, out),
 		newConfigSetCmd(h, out),
 		newConfigRemCmd(h, out),
+		newConfigUseContextCmd(h, out),
+		newConfigGetContextsCmd(h, out),
 	)
 
 	return cmd
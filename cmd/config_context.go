@@ -0,0 +1,96 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	configContextExample = `
+  # Add a context for a second hub
+  hctl config set contexts.lab.hub.url https://lab.example.com/api
+  hctl config set contexts.lab.hub.token YourToken
+
+  # List all contexts
+  hctl config get-contexts
+
+  # Switch to another context
+  hctl config use-context lab
+
+  # Use another context for a single command
+  hctl --context home toggle kitchen
+  HCTL_CONTEXT=home hctl toggle kitchen
+  `
+	// editorconfig-checker-enable
+)
+
+func compListContexts(args []string, h *pkg.Hctl) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return noMoreArgsComp()
+	}
+	return h.GetContexts(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func newConfigUseContextCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "use-context NAME",
+		Short:   "Set the current context",
+		Aliases: []string{"use"},
+		Example: configContextExample,
+		Args:    cobra.MatchAll(cobra.ExactArgs(1)),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return compListContexts(args, h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			if err := h.UseContext(args[0]); err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintSuccess(out, fmt.Sprintf("Switched to context `%s`.", args[0]))
+		},
+	}
+
+	return cmd
+}
+
+func newConfigGetContextsCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get-contexts",
+		Short:   "List all contexts",
+		Example: configContextExample,
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			header := []any{"CURRENT", "NAME", "URL"}
+			var clist [][]interface{}
+			for _, name := range h.GetContexts() {
+				current := ""
+				if name == h.GetContext() {
+					current = "*"
+				}
+				clist = append(clist, []any{current, name, h.GetContextHub(name).URL})
+			}
+			o.FprintSuccessListWithHeader(out, header, clist)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg"
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func newTestingHctlWithContexts(t *testing.T) *pkg.Hctl {
+	t.Helper()
+	ms := hctltest.MockServer(t)
+	t.Cleanup(ms.Close)
+	h := newTestingHctl(t)
+	for k, v := range map[string]string{
+		"contexts.lab.hub.url":           ms.URL,
+		"contexts.lab.hub.token":         "test",
+		"contexts.lab.device_map.lm":     "light.livingroom_main",
+		"contexts.parents.hub.url":       "http://127.1.33.7/api",
+		"contexts.parents.hub.token":     "test",
+		"contexts.parents.device_map.lm": "light.livingroom_corner",
+	} {
+		if err := h.SetConfigValueWrite(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return h
+}
+
+func Test_newCmdConfigContexts(t *testing.T) {
+	h := newTestingHctlWithContexts(t)
+
+	var tests = map[string]cmdTest{
+		"get contexts": {
+			"config get-contexts",
+			"(?m)^CURRENT\\s+NAME\\s+URL\\s*$\n^\\s+lab\\s+http://127.0.0.1:\\d+\\s*$\n^\\s+parents\\s+http://127.1.33.7/api\\s*$",
+			"",
+		},
+		"get context option": {
+			"config get contexts.parents.hub.url",
+			"(?m)^contexts.parents.hub.url\\s+http://127.1.33.7/api\\s*$",
+			"",
+		},
+		"switch context": {
+			"config use-context lab",
+			"(?m)^.*Switched to context `lab`.",
+			"",
+		},
+		"use current context": {
+			"on lm",
+			`(?m)^.*Living Main \(livingroom_main\) on`,
+			"",
+		},
+		"verify current context": {
+			"config get-contexts",
+			"(?m)^\\*\\s+lab\\s+",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_newCmdContextFlag(t *testing.T) {
+	h := newTestingHctlWithContexts(t)
+
+	var tests = map[string]cmdTest{
+		"context flag": {
+			"--context lab on lm",
+			`(?m)^.*Living Main \(livingroom_main\) on`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_newCmdContextEnv(t *testing.T) {
+	h := newTestingHctlWithContexts(t)
+	t.Setenv("HCTL_CONTEXT", "lab")
+
+	var tests = map[string]cmdTest{
+		"context from env": {
+			"toggle lm",
+			`(?m)^.*Living Main \(livingroom_main\) on`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_contextMaps(t *testing.T) {
+	h := newTestingHctlWithContexts(t)
+
+	// without a context the top-level maps are used
+	if got := h.GetMap("device_map")["a"]; got != "media_player.player1" {
+		t.Errorf("got %q, want media_player.player1", got)
+	}
+	if _, ok := h.GetMap("media_map")["party_horn"]; !ok {
+		t.Error("top-level media_map not used without context")
+	}
+
+	// a context only uses its own maps, as they belong to its hub
+	if err := h.UseContext("parents"); err != nil {
+		t.Fatal(err)
+	}
+	deviceMap := h.GetMap("device_map")
+	if _, ok := deviceMap["a"]; ok {
+		t.Error("top-level device_map used in context")
+	}
+	if got := deviceMap["lm"]; got != "light.livingroom_corner" {
+		t.Errorf("got %q, want light.livingroom_corner", got)
+	}
+	if _, ok := h.GetMap("media_map")["party_horn"]; ok {
+		t.Error("top-level media_map used in context")
+	}
+}
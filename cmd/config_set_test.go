@@ -27,7 +27,50 @@ func Test_newCmdConfigSet(t *testing.T) {
 			"(?m)^.*Option `completion.short_names` successfully set to `false`",
 			"",
 		},
+		"set hub.timeout option": {
+			"config set hub.timeout 10s",
+			"(?m)^.*Option `hub.timeout` successfully set to `10s`",
+			"",
+		},
+		"set hub.tls.insecure_skip_verify option": {
+			"config set hub.tls.insecure_skip_verify true",
+			"(?m)^.*Option `hub.tls.insecure_skip_verify` successfully set to `true`",
+			"",
+		},
+		"set hub.proxy option": {
+			"config set hub.proxy http://proxy.example.com:3128",
+			"(?m)^.*Option `hub.proxy` successfully set to `http://proxy.example.com:3128`",
+			"",
+		},
 	}
 
 	testCmd(t, h, tests)
 }
+
+func Test_configSetHubPath(t *testing.T) {
+	h := newTestingHctl(t)
+
+	tests := map[string]struct {
+		path    string
+		value   string
+		wantErr bool
+	}{
+		"timeout":         {"hub.timeout", "5s", false},
+		"tls timeout":     {"hub.tls.timeout", "5s", true},
+		"tls proxy":       {"hub.tls.proxy", "http://proxy.example.com:3128", true},
+		"nested url":      {"hub.foo.url", "http://other.example/api", true},
+		"tls ca_file":     {"hub.ca_file", "/dev/null", true},
+		"tls skip verify": {"hub.tls.insecure_skip_verify", "false", false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := h.SetConfigValue(tt.path, tt.value)
+			if tt.wantErr && err == nil {
+				t.Errorf("%s accepted, want error", tt.path)
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	coverExample = `
+  # Open a cover
+  hctl cover open kitchen_blinds
+
+  # Close all blinds and wait until they are closed
+  hctl cover close kitchen_blinds bedroom_blinds --wait
+
+  # Stop moving covers
+  hctl cover stop kitchen_blinds bedroom_blinds
+  `
+	// editorconfig-checker-enable
+)
+
+func newCoverCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cover",
+		Short:   "Open, close, stop and position covers",
+		Aliases: []string{"co"},
+		Example: coverExample + coverPositionExample + coverTiltExample,
+	}
+
+	cmd.AddCommand(
+		newCoverActionCmd(h, out, "open", "Open covers", "closed"),
+		newCoverActionCmd(h, out, "close", "Close covers", "open"),
+		newCoverActionCmd(h, out, "stop", "Stop moving covers", ""),
+		newCoverPositionCmd(h, out, false),
+		newCoverPositionCmd(h, out, true),
+	)
+
+	return cmd
+}
+
+// newCoverActionCmd creates a subcommand for action, state is the state of covers offered in completion
+func newCoverActionCmd(h *pkg.Hctl, out io.Writer, action string, short string, state string) *cobra.Command {
+	var wait time.Duration
+
+	cmd := &cobra.Command{
+		Use:   action + " COVER...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, state, h, "cover")
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			var hasErr bool
+			for _, cover := range args {
+				obj, done, results, err := h.CoverAction(cover, action, wait)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+				} else {
+					o.FprintResult(out, obj, done, results)
+				}
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	// a stopped cover has no state to wait for
+	if action != "stop" {
+		cmd.Use += " [--wait[=timeout]]"
+		addWaitFlag(cmd, &wait)
+	}
+
+	return cmd
+}
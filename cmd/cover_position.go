@@ -0,0 +1,110 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	// editorconfig-checker-disable
+	coverPositionExample = `
+  # Open a cover halfway
+  hctl cover position 50 kitchen_blinds
+
+  # Open covers 10% further
+  hctl cover position + kitchen_blinds bedroom_blinds
+  `
+	coverTiltExample = `
+  # Tilt the slats of a cover, 0 is closed and 100 fully open
+  hctl cover tilt 30 kitchen_blinds
+
+  # Close the slats 10% further and wait until the tilt is reached
+  hctl cover tilt - kitchen_blinds --wait
+  `
+	// editorconfig-checker-enable
+)
+
+var (
+	coverPositionRange = append([]string{"+", "-"}, util.MakeRangeString(0, 100)...)
+)
+
+// newCoverPositionCmd creates the position or (tilt=true) the tilt subcommand
+func newCoverPositionCmd(h *pkg.Hctl, out io.Writer, tilt bool) *cobra.Command {
+	var wait time.Duration
+	use, short, example, attr := "position", "Set the position of covers", coverPositionExample, "current_position"
+	if tilt {
+		use, short, example, attr = "tilt", "Set the tilt position of covers", coverTiltExample, "current_tilt_position"
+	}
+
+	cmd := &cobra.Command{
+		Use:     use + " [+|-|0-100] COVER... [--wait[=timeout]]",
+		Short:   short,
+		Example: example,
+		Args:    cobra.MatchAll(cobra.MinimumNArgs(2)),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return coverPositionRange, cobra.ShellCompDirectiveNoFileComp
+			}
+			return compListStatesMulti(toComplete, args[1:], nil, []string{attr}, "", h, "cover")
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateCoverPosition(args[0]); err != nil {
+				return err
+			}
+			if err := cmd.Root().PersistentPreRunE(cmd, args); err != nil {
+				return err
+			}
+			return nil
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			value := args[0]
+			var hasErr bool
+			for _, cover := range args[1:] {
+				obj, state, results, err := h.CoverPosition(cover, value, tilt, wait)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+				} else {
+					o.FprintResult(out, obj, state, results)
+				}
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	addWaitFlag(cmd, &wait)
+
+	return cmd
+}
+
+func validateCoverPosition(position string) error {
+	if !slices.Contains(coverPositionRange, position) {
+		return fmt.Errorf("position needs to be 0-100, or +/-")
+	}
+	return nil
+}
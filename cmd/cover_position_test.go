@@ -0,0 +1,61 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_coverPosition(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 position": {
+			"cover position 40 kitchen_blinds bedroom_blinds",
+			`(?s)Kitchen blinds \(kitchen_blinds\) position set to 40%.*Bedroom blinds \(bedroom_blinds\) position set to 40%`,
+			"",
+		},
+		"2 position up": {
+			"cover position + kitchen_blinds --wait",
+			`Kitchen blinds \(kitchen_blinds\) position set to 50%`,
+			"",
+		},
+		"3 position down": {
+			"cover position - cover.bedroom_blinds",
+			`Bedroom blinds \(bedroom_blinds\) position set to 30%`,
+			"",
+		},
+		"4 tilt": {
+			"cover tilt 25 kitchen_blinds",
+			`Kitchen blinds \(kitchen_blinds\) tilt set to 25%`,
+			"",
+		},
+		"5 tilt up": {
+			"cover tilt + kitchen_blinds",
+			`Kitchen blinds \(kitchen_blinds\) tilt set to 30%`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_cover(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 open": {
+			"cover open bedroom_blinds --wait",
+			`Bedroom blinds \(bedroom_blinds\) opened`,
+			"",
+		},
+		"2 stop": {
+			"cover stop kitchen_blinds bedroom_blinds",
+			`(?s)kitchen_blinds stopped: hub reported no state change.*bedroom_blinds stopped`,
+			"",
+		},
+		"3 close": {
+			"cover close cover.kitchen_blinds carport_gate",
+			`(?s)Kitchen blinds \(kitchen_blinds\) closed.*carport_gate closed: hub reported no state change`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
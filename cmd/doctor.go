@@ -0,0 +1,87 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	"github.com/xx4h/hctl/pkg/doctor"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	doctorExample = `
+  # Check config, hub connection, token and local media server
+  hctl doctor
+
+  # Check another context, e.g. in a cron job (exits non-zero if a check fails)
+  hctl doctor --context lab -o json
+  `
+	// editorconfig-checker-enable
+)
+
+func newDoctorCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check config, hub connection and local media server",
+		Long: "Check config file, hub url, token and its expiry, API, latency, clock skew, hub version and\n" +
+			"whether serve.ip and serve.port can be bound and reached. Exits non-zero if a check fails.",
+		Example: doctorExample,
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			checks := h.Doctor()
+			var warnings int
+			var rows [][]any
+			for _, c := range checks {
+				if c.Status == doctor.Warn {
+					warnings++
+				}
+				rows = append(rows, []any{strings.ToUpper(string(c.Status)), c.Name, c.Message})
+			}
+
+			if o.Structured() {
+				for _, c := range checks {
+					if err := o.FprintRecord(out, c); err != nil {
+						o.FprintError(out, err)
+					}
+				}
+			} else {
+				o.FprintSuccessListWithHeader(out, []any{"STATUS", "CHECK", "MESSAGE"}, rows)
+				fmt.Fprintln(out)
+				switch {
+				case doctor.Failed(checks):
+					o.FprintErrorMsg(out, fmt.Errorf("some checks failed"))
+				case warnings > 0:
+					o.FprintWarning(out, fmt.Sprintf("All checks passed, %d with warnings", warnings))
+				default:
+					o.FprintSuccess(out, "All checks passed")
+				}
+			}
+
+			if doctor.Failed(checks) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_doctor(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "hctl-test"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a free port for the serve check
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	ln.Close()
+	for k, v := range map[string]string{"hub.url": ms.URL, "hub.token": token, "serve.ip": "127.0.0.1", "serve.port": port} {
+		if err := h.SetConfigValue(k, v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	tests := map[string]cmdTest{
+		"report": {
+			"doctor",
+			`(?s)^STATUS\s+CHECK\s+MESSAGE\s*\n` +
+				`PASS\s+config\s+\S+/hctl.yaml\s*\n` +
+				`WARN\s+hub url\s+http://.*` +
+				`PASS\s+version\s+Home Assistant 2024.10.1 at Home.*` +
+				`All checks passed, 1 with warnings`,
+			"",
+		},
+		"json": {
+			"doctor -o json",
+			`\{"check":"token","status":"pass","message":"valid, does not expire"\}\n`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
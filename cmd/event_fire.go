@@ -0,0 +1,70 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	eventFireExample = `
+  # Fire a custom event, e.g. to trigger automations from scripts or git hooks
+  hctl event fire deploy_finished
+
+  # Fire an event with data as key=value (values are parsed as JSON) or JSON object
+  hctl event fire garage_door_reminder --data minutes=15 --data notify=true
+  hctl event fire backup_done --json '{"host": "nas", "size_gb": 42.5}'
+  `
+	// editorconfig-checker-enable
+)
+
+func newEventFireCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var data []string
+	var jsonData string
+
+	cmd := &cobra.Command{
+		Use:   "fire EVENT_TYPE [--data key=value]... [--json '{...}']",
+		Short: "Fire an event",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return noMoreArgsComp()
+			}
+			return compListEvents(toComplete, h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			payload, err := parseData(data, jsonData)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			msg, err := h.FireEvent(args[0], payload)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintSuccess(out, msg)
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVarP(&data, "data", "d", []string{}, "Event data as key=value, values are parsed as JSON if possible (e.g. minutes=15)")
+	cmd.PersistentFlags().StringVar(&jsonData, "json", "", "Event data as JSON object, --data takes precedence")
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_eventList(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		"list": {
+			"event list",
+			`^EVENT\s+LISTENERS\s*\n` +
+				`automation_triggered\s+1\s*\n` +
+				`call_service\s+3\s*\n` +
+				`garage_door_reminder\s+1\s*\n` +
+				`homeassistant_start\s+4\s*\n` +
+				`state_changed\s+12\s*\n`,
+			"",
+		},
+		"tsv": {
+			"event ls -o tsv",
+			"^event\tlisteners\nautomation_triggered\t1\n",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	// editorconfig-checker-disable
+	fanExample = `
+  # Set the speed of a fan
+  hctl fan percentage bedroom_fan 40
+
+  # Step the speed of all fans up by their percentage_step
+  hctl fan percentage bedroom_fan attic_fan +
+
+  # Set the preset mode and wait until the fan is on
+  hctl fan preset bedroom_fan sleep --wait
+
+  # Turn oscillation on and reverse the direction
+  hctl fan oscillate bedroom_fan on
+  hctl fan direction bedroom_fan reverse
+  `
+	// editorconfig-checker-enable
+)
+
+var (
+	fanPercentageRange = append([]string{"+", "-"}, util.MakeRangeString(0, 100)...)
+)
+
+// fanSetting is a subcommand setting VALUE, the last argument, for all fans given before it
+type fanSetting struct {
+	use     string
+	short   string
+	aliases []string
+	// attribute fans need to have to be offered in completion
+	attribute string
+	// values offered in completion, the only valid values if valid (their description) is set
+	values func(args []string) []string
+	valid  string
+	// wait adds --wait, its timeout is passed to set
+	wait bool
+	set  func(fan string, value string, wait time.Duration) (string, string, []rest.HassResult, error)
+}
+
+func newFanCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fan",
+		Short:   "Set speed, preset mode, oscillation and direction of fans",
+		Example: fanExample,
+	}
+
+	cmd.AddCommand(
+		newFanSettingCmd(h, out, fanSetting{
+			use:       "percentage",
+			short:     "Set the speed of fans",
+			aliases:   []string{"speed"},
+			attribute: "percentage_step",
+			values:    func([]string) []string { return fanPercentageRange },
+			valid:     "0-100, or +/-",
+			wait:      true,
+			set:       h.FanPercentage,
+		}),
+		newFanSettingCmd(h, out, fanSetting{
+			use:       "preset",
+			short:     "Set the preset mode of fans",
+			aliases:   []string{"mode"},
+			attribute: "preset_modes",
+			values:    func(args []string) []string { return compListPresetModes(args, h) },
+			wait:      true,
+			set:       h.FanPresetMode,
+		}),
+		newFanSettingCmd(h, out, fanSetting{
+			use:       "oscillate",
+			short:     "Turn oscillation of fans on or off",
+			attribute: "oscillating",
+			values:    func([]string) []string { return []string{"on", "off"} },
+			valid:     "on or off",
+			set: func(fan string, value string, _ time.Duration) (string, string, []rest.HassResult, error) {
+				return h.FanOscillate(fan, value == "on")
+			},
+		}),
+		newFanSettingCmd(h, out, fanSetting{
+			use:       "direction",
+			short:     "Set the direction of fans",
+			attribute: "direction",
+			values:    func([]string) []string { return rest.FanDirections },
+			valid:     strings.Join(rest.FanDirections, " or "),
+			set: func(fan string, value string, _ time.Duration) (string, string, []rest.HassResult, error) {
+				return h.FanDirection(fan, value)
+			},
+		}),
+	)
+
+	return cmd
+}
+
+func newFanSettingCmd(h *pkg.Hctl, out io.Writer, s fanSetting) *cobra.Command {
+	var wait time.Duration
+
+	cmd := &cobra.Command{
+		Use:     s.use + " FAN... VALUE",
+		Short:   s.short,
+		Aliases: s.aliases,
+		Args:    cobra.MatchAll(cobra.MinimumNArgs(2)),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return compListStates(toComplete, args, nil, []string{s.attribute}, "", h, "fan")
+			}
+			// Offer both fans and values for subsequent args
+			fans, directive := compListStatesMulti(toComplete, args, nil, []string{s.attribute}, "", h, "fan")
+			return append(fans, s.values(args)...), directive
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			value := args[len(args)-1]
+			fans := args[:len(args)-1]
+			if s.valid != "" && !slices.Contains(s.values(fans), value) {
+				o.FprintError(out, fmt.Errorf("%s needs to be %s", s.use, s.valid))
+			}
+			var hasErr bool
+			for _, fan := range fans {
+				obj, state, results, err := s.set(fan, value, wait)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+				} else {
+					o.FprintResult(out, obj, state, results)
+				}
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	if s.wait {
+		cmd.Use += " [--wait[=timeout]]"
+		addWaitFlag(cmd, &wait)
+	}
+
+	return cmd
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_fan(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 percentage": {
+			"fan percentage bedroom_fan fan.attic_fan 60",
+			`(?s)Bedroom fan \(bedroom_fan\) speed set to 60%.*Attic fan \(attic_fan\) speed set to 60%`,
+			"",
+		},
+		"2 percentage up": {
+			"fan speed bedroom_fan attic_fan + --wait",
+			`(?s)Bedroom fan \(bedroom_fan\) speed set to 70%.*Attic fan \(attic_fan\) speed set to 67%`,
+			"",
+		},
+		"3 percentage down": {
+			"fan percentage attic_fan -",
+			`Attic fan \(attic_fan\) speed set to 33%`,
+			"",
+		},
+		"4 preset": {
+			"fan preset bedroom_fan nature --wait=2s",
+			`Bedroom fan \(bedroom_fan\) preset mode set to nature`,
+			"",
+		},
+		"5 oscillate": {
+			"fan oscillate bedroom_fan on",
+			`Bedroom fan \(bedroom_fan\) oscillation turned on`,
+			"",
+		},
+		"6 direction": {
+			"fan direction bedroom_fan reverse",
+			`Bedroom fan \(bedroom_fan\) direction set to reverse`,
+			"",
+		},
+		"7 percentage off": {
+			"fan percentage attic_fan 0 --wait=2s",
+			`Attic fan \(attic_fan\) speed set to 0%`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
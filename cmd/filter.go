@@ -43,6 +43,21 @@ func filterStates(states []rest.HassState, ignoredStates []string) []rest.HassSt
 	return filteredStates
 }
 
+func filterDomains(states []rest.HassState, domains []string) []rest.HassState {
+	if len(domains) == 0 {
+		return states
+	}
+
+	var filteredStates []rest.HassState
+	for _, rel := range states {
+		if domain, _, _ := strings.Cut(rel.EntityID, "."); slices.Contains(domains, domain) {
+			filteredStates = append(filteredStates, rel)
+		}
+	}
+
+	return filteredStates
+}
+
 func filterAttributes(states []rest.HassState, attributes []string) []rest.HassState {
 	if attributes == nil {
 		return states
@@ -0,0 +1,113 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	getExample = `
+  # Show state and attributes of an entity (short names, device_map and fuzzy matching work as usual)
+  hctl get bedroom_main
+
+  # Print only the state, e.g. for use in scripts
+  echo "It is $(hctl get temp_sensor --raw) degrees outside"
+  `
+	// editorconfig-checker-enable
+)
+
+func newGetCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var raw bool
+
+	cmd := &cobra.Command{
+		Use:     "get ENTITY [--raw]",
+		Short:   "Show state and attributes of an entity",
+		Example: getExample,
+		Args:    cobra.MatchAll(cobra.ExactArgs(1)),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return noMoreArgsComp()
+			}
+			return compListStates(toComplete, args, nil, nil, "", h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			s, err := h.GetEntity(args[0])
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			if raw {
+				fmt.Fprintln(out, s.State)
+				return
+			}
+			if o.Structured() {
+				if err := o.FprintRecord(out, s); err != nil {
+					o.FprintError(out, err)
+				}
+				return
+			}
+
+			o.FprintSuccessListWithHeader(out,
+				[]any{"ENTITY", "STATE", "LAST CHANGED", "LAST UPDATED"},
+				[][]any{{s.EntityID, s.State, formatTimestamp(s.LastChanged), formatTimestamp(s.LastUpdated)}})
+			fmt.Fprintln(out)
+
+			var attrs [][]any
+			for _, k := range slices.Sorted(maps.Keys(s.Attributes)) {
+				attrs = append(attrs, []any{k, formatAttribute(s.Attributes[k])})
+			}
+			o.FprintSuccessListWithHeader(out, []any{"ATTRIBUTE", "VALUE"}, attrs)
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&raw, "raw", false, "Print only the state value")
+
+	return cmd
+}
+
+// formatTimestamp prints timestamps of the hub in local time, "-" if there is none
+func formatTimestamp(ts string) string {
+	if ts == "" {
+		return "-"
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// formatAttribute prints strings as they are and everything else as JSON
+func formatAttribute(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
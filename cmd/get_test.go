@@ -0,0 +1,57 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_newCmdGet(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+	if err := h.SetConfigValue("handling.fuzz", "true"); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"get entity": {
+			"get light.bedroom_main",
+			`(?s)ENTITY\s+STATE\s+LAST CHANGED\s+LAST UPDATED\s*\nlight.bedroom_main\s+on\s+\d{4}-\d{2}-\d{2} .*ATTRIBUTE\s+VALUE.*\nbrightness\s+207\s*\n.*friendly_name\s+Bedroom Main`,
+			"",
+		},
+		"get short name": {
+			"get heating",
+			`(?s)climate.heating\s+heat.*temperature\s+21`,
+			"",
+		},
+		"get mapped name raw": {
+			"get a --raw",
+			`^on\n$`,
+			"",
+		},
+		"get fuzzy raw": {
+			"get lvngcrnr --raw",
+			`^on\n$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	// editorconfig-checker-disable
+	historyExample = `
+  # When did the garage door open last night?
+  hctl history garage_door --since 12h
+
+  # State changes of two entities in a fixed period
+  hctl history garage_door front_door --since "2024-10-08 18:00" --until "2024-10-09 08:00"
+
+  # Temperature of the last 24 hours as sparkline
+  hctl history outdoor_temperature --sparkline
+
+  # Include attribute-only changes and export as CSV
+  hctl history outdoor_temperature --significant-changes-only=false -o csv > temperature.csv
+  `
+	// editorconfig-checker-enable
+)
+
+func newHistoryCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var since, until string
+	var minimal, significantOnly, sparkline bool
+	var width int
+
+	cmd := &cobra.Command{
+		Use:     "history ENTITY... [--since 24h] [--until TIME]",
+		Short:   "Show recorded state changes of entities",
+		Example: historyExample,
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			now := time.Now()
+			opts := rest.HistoryOptions{Until: now, Minimal: minimal, SignificantOnly: significantOnly}
+			var err error
+			if opts.Since, err = util.ParseTime(since, now); err != nil {
+				o.FprintError(out, err)
+			}
+			if until != "" {
+				if opts.Until, err = util.ParseTime(until, now); err != nil {
+					o.FprintError(out, err)
+				}
+			}
+			if !opts.Since.Before(opts.Until) {
+				o.FprintError(out, fmt.Errorf("--since must be before --until"))
+			}
+
+			history, err := h.GetHistory(args, opts)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+
+			if sparkline && !o.Structured() {
+				printSparklines(out, history, opts.Since, opts.Until, width)
+				return
+			}
+			printHistory(out, history)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&since, "since", "24h", "Start of the period, a duration before now or a timestamp")
+	cmd.PersistentFlags().StringVar(&until, "until", "", "End of the period, a duration before now or a timestamp (default now)")
+	cmd.PersistentFlags().BoolVar(&minimal, "minimal", false, "Only fetch state and time of changes (minimal_response)")
+	cmd.PersistentFlags().BoolVar(&significantOnly, "significant-changes-only", true, "Leave out attribute-only changes (significant_changes_only)")
+	cmd.PersistentFlags().BoolVarP(&sparkline, "sparkline", "s", false, "Render numeric entities as sparkline")
+	cmd.PersistentFlags().IntVar(&width, "width", 48, "Width of the sparkline")
+
+	return cmd
+}
+
+// printHistory prints the states of all entities ordered by time
+func printHistory(out io.Writer, history [][]rest.HassState) {
+	var states []rest.HassState
+	for _, s := range history {
+		states = append(states, s...)
+	}
+	// timestamps of the hub are all UTC, so they sort as strings
+	slices.SortStableFunc(states, func(a, b rest.HassState) int {
+		return strings.Compare(a.LastChanged, b.LastChanged)
+	})
+
+	if o.Structured() {
+		for _, s := range states {
+			r := o.Row{Keys: []string{"entity_id", "state", "last_changed", "attributes"}, Values: []any{s.EntityID, s.State, s.LastChanged, s.Attributes}}
+			if err := o.FprintRecord(out, r); err != nil {
+				o.FprintError(out, err)
+			}
+		}
+		return
+	}
+	if len(states) == 0 {
+		o.FprintWarning(out, "No states recorded in this period")
+		return
+	}
+	var rows [][]any
+	for _, s := range states {
+		rows = append(rows, []any{formatTimestamp(s.LastChanged), s.EntityID, s.State})
+	}
+	o.FprintSuccessListWithHeader(out, []any{"TIME", "ENTITY", "STATE"}, rows)
+}
+
+// printSparklines prints one sparkline per numeric entity with its minimum, maximum and last value
+func printSparklines(out io.Writer, history [][]rest.HassState, since time.Time, until time.Time, width int) {
+	var rows [][]any
+	for _, states := range history {
+		if len(states) == 0 {
+			continue
+		}
+		values, ok := pkg.Resample(states, since, until, width)
+		if !ok {
+			o.FprintWarning(out, fmt.Sprintf("%s has no numeric states, use it without --sparkline", states[0].EntityID))
+			continue
+		}
+		unit, _ := states[0].Attributes["unit_of_measurement"].(string)
+		lo, hi, last := minMaxLast(states)
+		rows = append(rows, []any{states[0].EntityID, o.Sparkline(values), withUnit(lo, unit), withUnit(hi, unit), withUnit(last, unit)})
+	}
+	if len(rows) == 0 {
+		return
+	}
+	o.FprintSuccessListWithHeader(out, []any{"ENTITY", "HISTORY", "MIN", "MAX", "LAST"}, rows)
+}
+
+func minMaxLast(states []rest.HassState) (float64, float64, float64) {
+	var lo, hi, last float64
+	first := true
+	for _, s := range states {
+		v, err := strconv.ParseFloat(s.State, 64)
+		if err != nil {
+			continue
+		}
+		if first || v < lo {
+			lo = v
+		}
+		if first || v > hi {
+			hi = v
+		}
+		last, first = v, false
+	}
+	return lo, hi, last
+}
+
+func withUnit(v float64, unit string) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if unit == "" {
+		return s
+	}
+	return fmt.Sprintf("%s %s", s, unit)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_history(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	period := "--since 2024-10-08T18:00:00Z --until 2024-10-09T08:00:00Z"
+	tests := map[string]cmdTest{
+		"garage door": {
+			"history garage_door " + period,
+			`^TIME\s+ENTITY\s+STATE\s*\n` +
+				`(\S+ \S+\s+binary_sensor.garage_door\s+(on|off)\s*\n){4}$`,
+			"",
+		},
+		"multiple entities json": {
+			"history garage_door sensor.outdoor_temperature " + period + " --minimal -o json",
+			`^(\{"entity_id":"(binary_sensor.garage_door|sensor.outdoor_temperature)","state":"[^"]+","last_changed":"[^"]+","attributes":.*\}\n){9}$`,
+			"",
+		},
+		"csv": {
+			"history garage_door " + period + " -o csv",
+			"^entity_id,state,last_changed,attributes\n" +
+				`binary_sensor.garage_door,on,2024-10-08T22:47:12\+00:00,"\{""device_class"":""garage_door"".*\}"\n`,
+			"",
+		},
+		"sparkline": {
+			"history outdoor_temperature --sparkline --width 8 " + period,
+			`^ENTITY\s+HISTORY\s+MIN\s+MAX\s+LAST\s*\n` +
+				`sensor.outdoor_temperature\s+[▁▂▃▄▅▆▇█]{8}\s+8.2 °C\s+15 °C\s+8.9 °C\s*\n$`,
+			"",
+		},
+		"sparkline not numeric": {
+			"history garage_door --sparkline " + period,
+			"binary_sensor.garage_door has no numeric states",
+			"",
+		},
+		"empty period": {
+			"history garage_door --since 2024-01-01T00:00:00Z --until 2024-01-02T00:00:00Z",
+			"No states recorded in this period",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
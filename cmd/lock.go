@@ -0,0 +1,79 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+)
+
+const (
+	// editorconfig-checker-disable
+	lockExample = `
+  # Lock a lock, locks are never matched fuzzy
+  hctl lock front_door
+
+  # Lock all doors, using a code
+  hctl lock front_door back_door --code 1234
+  `
+	unlockExample = `
+  # Unlock a lock, asks for confirmation if it is listed in protected_entities
+  hctl unlock front_door
+
+  # Unlock a protected lock from a script, without confirmation
+  hctl unlock front_door --code 1234 --yes
+  `
+	// editorconfig-checker-enable
+)
+
+func newLockCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	return newLockActionCmd(h, out, true)
+}
+
+func newUnlockCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	return newLockActionCmd(h, out, false)
+}
+
+// newLockActionCmd creates the lock or (lock=false) the unlock command
+func newLockActionCmd(h *pkg.Hctl, out io.Writer, lock bool) *cobra.Command {
+	var code string
+	a := serviceAction{domains: []string{"lock"}, service: "lock", verb: "lock", done: "locked"}
+	short, example, state := "Lock a lock", lockExample, "unlocked"
+	if !lock {
+		a.service, a.verb, a.done = "unlock", "unlock", "unlocked"
+		short, example, state = "Unlock a lock", unlockExample, "locked"
+	}
+
+	cmd := &cobra.Command{
+		Use:     a.verb + " LOCK... [--code CODE] [-y|--yes]",
+		Short:   short,
+		Example: example,
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, state, h, a.domains...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			a.data = codeData(code)
+			a.run(h, out, args)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&code, "code", "", "Code to "+a.verb+" the lock, if required")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_lock(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		"lock": {
+			"lock shed",
+			`Shed \(shed\) locked`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_unlock(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		"protected with yes": {
+			"unlock front_door --code 1234 --yes",
+			`Front door \(front_door\) unlocked`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_unlockConfirm(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	// lock.shed is not protected, so only lock.front_door asks
+	args := []string{"unlock", "front_door", "shed"}
+	out := new(bytes.Buffer)
+	errout := new(bytes.Buffer)
+	rootCmd = newRootCmd(h, out, args)
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errout)
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil {
+		t.Error(err)
+	}
+	if got, want := errout.String(), "lock.front_door is protected, really call lock.unlock? [y/N] "; got != want {
+		t.Errorf("got prompt %q, want %q", got, want)
+	}
+	if got := out.String(); !strings.Contains(got, "(front_door) unlocked") || !strings.Contains(got, "shed unlocked") {
+		t.Errorf("got %s, want both locks unlocked", got)
+	}
+}
+
+func Test_confirm(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want bool
+	}{
+		"yes":           {"yes\n", true},
+		"y":             {"Y\n", true},
+		"no":            {"n\n", false},
+		"empty":         {"\n", false},
+		"no input":      {"", false},
+		"without eol":   {"y", true},
+		"anything else": {"sure\n", false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			prompt := new(bytes.Buffer)
+			if got := confirm(bufio.NewReader(strings.NewReader(tt.in)), prompt, "Really?"); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+			if !strings.HasPrefix(prompt.String(), "Really? [y/N] ") {
+				t.Errorf("got prompt %q", prompt.String())
+			}
+		})
+	}
+}
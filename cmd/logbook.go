@@ -0,0 +1,109 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	// editorconfig-checker-disable
+	logbookExample = `
+  # Show the logbook of the last 24 hours
+  hctl logbook
+
+  # Who turned on the light? (short names, device_map and fuzzy matching work as usual)
+  hctl logbook livingroom_main --since 2h
+
+  # All automations of last night mentioning the garage door
+  hctl logbook -d automation --search garage --since "2024-10-08 18:00" --until "2024-10-09 08:00"
+
+  # Follow new entries of all lights and switches
+  hctl logbook -d light -d switch --since 5m --follow
+  `
+	// editorconfig-checker-enable
+)
+
+func newLogbookCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var domains []string
+	var since, until, search string
+	var follow bool
+	var interval time.Duration
+	var count int
+
+	cmd := &cobra.Command{
+		Use:     "logbook [ENTITY|GLOB...]",
+		Short:   "Show logbook entries, what happened and what caused it",
+		Example: logbookExample,
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			now := time.Now()
+			opts := pkg.LogbookOptions{Until: now, Follow: follow, Interval: interval, Count: count}
+			var err error
+			if opts.Since, err = util.ParseTime(since, now); err != nil {
+				o.FprintError(out, err)
+			}
+			if until != "" {
+				if follow {
+					o.FprintError(out, fmt.Errorf("--until can't be used with --follow"))
+				}
+				if opts.Until, err = util.ParseTime(until, now); err != nil {
+					o.FprintError(out, err)
+				}
+			}
+			if !opts.Since.Before(opts.Until) {
+				o.FprintError(out, fmt.Errorf("--since must be before --until"))
+			}
+			if follow && interval <= 0 {
+				o.FprintError(out, fmt.Errorf("--interval must be positive"))
+			}
+
+			filter, err := h.NewLogbookFilter(args, domains, search)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			if err := h.Logbook(ctx, out, filter, opts); err != nil {
+				o.FprintError(out, err)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVarP(&domains, "domains", "d", []string{}, "Limit domains")
+	cmd.PersistentFlags().StringVarP(&search, "search", "s", "", "Only show entries containing this text (case-insensitive)")
+	cmd.PersistentFlags().StringVar(&since, "since", "24h", "Start of the period, a duration before now or a timestamp")
+	cmd.PersistentFlags().StringVar(&until, "until", "", "End of the period, a duration before now or a timestamp (default now)")
+	cmd.PersistentFlags().BoolVarP(&follow, "follow", "f", false, "Keep polling for new entries")
+	cmd.PersistentFlags().DurationVar(&interval, "interval", 5*time.Second, "Polling interval with --follow")
+	cmd.PersistentFlags().IntVarP(&count, "count", "n", 0, "Exit after n entries")
+
+	return cmd
+}
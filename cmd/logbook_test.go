@@ -0,0 +1,68 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_logbook(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	period := "--since 2024-10-08T18:00:00Z --until 2024-10-09T12:00:00Z"
+	line := `\S+ \S+  `
+	tests := map[string]cmdTest{
+		"all": {
+			"logbook " + period,
+			`^(` + line + `[^\n]+\n){6}$`,
+			"",
+		},
+		"entity with cause": {
+			"logbook livingroom_main " + period,
+			`^` + line + `light.livingroom_main\s+Living Main turned on  \(by Some automation \(automation.some_automation\)\)\n$`,
+			"",
+		},
+		"domain without entity": {
+			"logbook -d homeassistant " + period,
+			`^` + line + `homeassistant\s+Home Assistant started\n$`,
+			"",
+		},
+		"search": {
+			"logbook --search GARAGE " + period,
+			`^(` + line + `[^\n]+\n){4}$`,
+			"",
+		},
+		"glob and domain json": {
+			"logbook livingroom_* -d switch " + period + " -o json",
+			`^\{"when":"2024-10-09T06:30:41.672508\+00:00","name":"Living Warp","message":"turned on","entity_id":"switch.livingroom_warp",.*"context_user_id":"9f2c4a7e61b04d2f8d1c3e5b7a9f0d12".*\}\n$`,
+			"",
+		},
+		"follow with count": {
+			"logbook binary_sensor.garage_door --since 2024-10-08T18:00:00Z --follow --interval 10ms -n 2",
+			`^` + line + `binary_sensor.garage_door\s+Garage Door was opened\n` +
+				line + `binary_sensor.garage_door\s+Garage Door was closed\n$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
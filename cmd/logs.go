@@ -0,0 +1,99 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+const (
+	// editorconfig-checker-disable
+	logsExample = `
+  # Show the log of the hub
+  hctl logs
+
+  # Show the last 20 warnings and errors of the mqtt integration
+  hctl logs --level warning --logger mqtt --tail 20
+
+  # Follow errors mentioning the garage
+  hctl logs --level error --grep '(?i)garage' --follow
+  `
+	// editorconfig-checker-enable
+)
+
+func newLogsCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var level, pattern string
+	var loggers []string
+	var tail int
+	var follow bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:     "logs",
+		Short:   "Show and follow the log of the hub",
+		Example: logsExample,
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if follow && interval <= 0 {
+				o.FprintError(out, fmt.Errorf("--interval must be positive"))
+			}
+			filter, err := pkg.NewLogFilter(level, loggers, pattern)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			opts := pkg.LogsOptions{Tail: tail, Follow: follow, Interval: interval}
+			if err := h.Logs(ctx, out, filter, opts); err != nil {
+				o.FprintError(out, err)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&level, "level", "", fmt.Sprintf("Minimum level (%s)", strings.ToLower(strings.Join(rest.LogLevels, ", "))))
+	cmd.PersistentFlags().StringArrayVar(&loggers, "logger", []string{}, "Limit to integrations (e.g. mqtt) or logger names (e.g. homeassistant.setup)")
+	cmd.PersistentFlags().StringVarP(&pattern, "grep", "g", "", "Only show entries matching this regular expression")
+	cmd.PersistentFlags().IntVarP(&tail, "tail", "n", 0, "Only show the last n entries (0 shows all)")
+	cmd.PersistentFlags().BoolVarP(&follow, "follow", "f", false, "Keep polling for new entries")
+	cmd.PersistentFlags().DurationVar(&interval, "interval", 5*time.Second, "Polling interval with --follow")
+
+	err := cmd.RegisterFlagCompletionFunc("level", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		var levels []string
+		for _, l := range rest.LogLevels {
+			levels = append(levels, strings.ToLower(l))
+		}
+		return levels, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		log.Error().Msgf("Could not register flag completion func for level: %+v", err)
+	}
+
+	return cmd
+}
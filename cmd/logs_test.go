@@ -0,0 +1,144 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg"
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_logs(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	entry := `2024-10-09 \S+ `
+	tests := map[string]cmdTest{
+		"all": {
+			"logs",
+			`^(` + entry + `[^\n]+\n){5}(Traceback.*\n\s+File.*\n.*\n\S+ServiceNotFound.*\n)(` + entry + `[^\n]+\n){2}$`,
+			"",
+		},
+		"level": {
+			"logs --level error",
+			`^` + entry + `ERROR .*mqtt.client.*\n` +
+				entry + `ERROR .*garage_light.*\n(.*\n){4}` +
+				entry + `CRITICAL .*\n$`,
+			"",
+		},
+		"integration": {
+			"logs --logger mqtt",
+			`^` + entry + `ERROR \(MainThread\) \[homeassistant.components.mqtt.client\].*\n` +
+				entry + `CRITICAL \(MainThread\) \[homeassistant.components.mqtt\].*\n$`,
+			"",
+		},
+		"logger and custom integration": {
+			"logs --logger homeassistant.setup --logger hacs",
+			`^` + entry + `WARNING \(MainThread\) \[homeassistant.setup\].*\n` +
+				entry + `DEBUG \(MainThread\) \[custom_components.hacs\].*\n$`,
+			"",
+		},
+		"grep in traceback": {
+			"logs -g ServiceNotFound",
+			`^` + entry + `ERROR .*garage_light.*\n(.*\n){4}$`,
+			"",
+		},
+		"tail": {
+			"logs --tail 2 --level info",
+			`^` + entry + `ERROR .*garage_light.*\n(.*\n){4}` + entry + `CRITICAL .*\n$`,
+			"",
+		},
+		"json": {
+			"logs --level critical -o json",
+			`^\{"time":"2024-10-09 06:31:02.118","level":"CRITICAL","thread":"MainThread","logger":"homeassistant.components.mqtt","message":"MQTT connection lost, entities will be unavailable"\}\n$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_logsFollowContinuation(t *testing.T) {
+	// the traceback of the error is written after the first poll
+	logs := []string{
+		"2024-10-09 06:13:16.010 ERROR (MainThread) [homeassistant.components.automation.garage_light] Error executing script\n",
+		"Traceback (most recent call last):\nhomeassistant.exceptions.ServiceNotFound: Service light.turn_onn not found\n" +
+			"2024-10-09 06:30:41.672 DEBUG (MainThread) [custom_components.hacs] Checking repositories\n",
+	}
+	var polls atomic.Int32
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/error_log" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		n := min(int(polls.Add(1)), len(logs))
+		if _, err := w.Write([]byte(strings.Join(logs[:n], ""))); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}))
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]struct {
+		level   string
+		pattern string
+		want    string
+	}{
+		"level": {
+			level: "error",
+			want: `^2024-10-09 \S+ ERROR .*Error executing script\n` +
+				`Traceback.*\n.*ServiceNotFound.*\n$`,
+		},
+		"pattern in continuation": {
+			pattern: "ServiceNotFound",
+			want:    `^2024-10-09 \S+ ERROR .*Error executing script\nTraceback.*\n.*ServiceNotFound.*\n$`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			polls.Store(0)
+			filter, err := pkg.NewLogFilter(tt.level, nil, tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			out := new(bytes.Buffer)
+			if err := h.Logs(ctx, out, filter, pkg.LogsOptions{Follow: true, Interval: 10 * time.Millisecond}); err != nil {
+				t.Fatal(err)
+			}
+			if !regexp.MustCompile(tt.want).MatchString(out.String()) {
+				t.Errorf("got %q, want %s", out.String(), tt.want)
+			}
+		})
+	}
+}
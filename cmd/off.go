@@ -15,43 +15,51 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/xx4h/hctl/pkg"
 	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
 )
 
 func newOffCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 	var transition float64
+	var wait time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "off [--transition seconds]",
+		Use:   "off [--transition seconds] [--wait[=timeout]]",
 		Short: "Switch or turn off a light or switch",
 		Args:  cobra.MatchAll(cobra.MinimumNArgs(1)),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compListStatesMulti(toComplete, args, []string{"turn_off"}, nil, "on", h)
 		},
 		Run: func(_ *cobra.Command, args []string) {
-			c := h.GetRest()
+			c := h.GetBackend()
 			hasTransition := transition != 0
 			var hasErr bool
 			for _, device := range args {
 				var obj, state, sub string
+				var results []rest.HassResult
 				var err error
 				if hasTransition {
-					obj, state, sub, err = c.TurnLightOffTransition(device, transition)
+					obj, state, sub, results, err = c.TurnLightOffTransition(device, transition)
 				} else {
-					obj, state, sub, err = c.TurnOff(device)
+					obj, state, sub, results, err = c.TurnOff(device)
+				}
+				if err == nil {
+					results, err = waitForTarget(c, wait, results)
 				}
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccessAction(out, obj, state)
+					o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), "", results)
 				}
 				log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 			}
@@ -62,6 +70,7 @@ func newOffCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 	}
 
 	cmd.PersistentFlags().Float64VarP(&transition, "transition", "s", 0, "Set transition time in seconds (e.g. 1.5)")
+	addWaitFlag(cmd, &wait)
 
 	return cmd
 }
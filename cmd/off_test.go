@@ -30,12 +30,22 @@ func Test_newCmdOff(t *testing.T) {
 	var tests = map[string]cmdTest{
 		"turn off": {
 			"off light.bedroom_other",
-			"(?m)^.*bedroom_other off",
+			`(?m)^.*Bedroom Other \(bedroom_other\) off`,
+			"",
+		},
+		"turn off already off": {
+			"off light.livingroom_main",
+			"(?m)^.*livingroom_main: hub reported no state change",
 			"",
 		},
 		"turn off multiple": {
-			"off light.bedroom_other light.bedroom_main",
-			"(?s).*bedroom_other off.*bedroom_main off",
+			"off light.livingroom_corner media_player.player2",
+			`(?s).*livingroom_corner\) off.*player2\) off`,
+			"",
+		},
+		"turn off and wait": {
+			"off light.bedroom_main --wait=2s",
+			`(?m)^.*bedroom_main\) off`,
 			"",
 		},
 	}
@@ -15,14 +15,17 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/xx4h/hctl/pkg"
 	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
 )
 
 func newOnCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
@@ -30,20 +33,20 @@ func newOnCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 	var color string
 	var colorTemp int
 	var transition float64
+	var wait time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "on [-b|--brightness +|-|min|max|1-99] [-c|--color R,G,B] [-t|--color-temp 1000-10000] [--transition seconds]",
+		Use:   "on [-b|--brightness +|-|min|max|1-99] [-c|--color R,G,B] [-t|--color-temp 1000-10000] [--transition seconds] [--wait[=timeout]]",
 		Short: "Switch or turn on a light or switch",
 		Args:  cobra.MatchAll(cobra.MinimumNArgs(1)),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compListStatesMulti(toComplete, args, []string{"turn_on"}, nil, "off", h)
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if brightness == "" {
-				return nil
-			}
-			if err := validateBrightness(brightness); err != nil {
-				return err
+			if brightness != "" {
+				if err := validateBrightness(brightness); err != nil {
+					return err
+				}
 			}
 			if err := cmd.Root().PersistentPreRunE(cmd, args); err != nil {
 				return err
@@ -51,22 +54,26 @@ func newOnCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 			return nil
 		},
 		Run: func(_ *cobra.Command, args []string) {
-			c := h.GetRest()
+			c := h.GetBackend()
 			hasCustom := brightness != "" || color != "" || colorTemp != 0 || transition != 0
 			var hasErr bool
 			for _, device := range args {
 				var obj, state, sub string
+				var results []rest.HassResult
 				var err error
 				if hasCustom {
-					obj, state, sub, err = c.TurnLightOnCustom(device, brightness, color, colorTemp, transition)
+					obj, state, sub, results, err = c.TurnLightOnCustom(device, brightness, color, colorTemp, transition)
 				} else {
-					obj, state, sub, err = c.TurnOn(device)
+					obj, state, sub, results, err = c.TurnOn(device)
+				}
+				if err == nil {
+					results, err = waitForTarget(c, wait, results)
 				}
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccessAction(out, obj, state)
+					o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), "", results)
 				}
 				log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 			}
@@ -80,6 +87,7 @@ func newOnCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&color, "color", "c", "", "Set RGB color in format R,G,B")
 	cmd.PersistentFlags().IntVarP(&colorTemp, "color-temp", "t", 0, "Set color temperature in Kelvin (1000-10000)")
 	cmd.PersistentFlags().Float64VarP(&transition, "transition", "s", 0, "Set transition time in seconds (e.g. 1.5)")
+	addWaitFlag(cmd, &wait)
 	err := cmd.RegisterFlagCompletionFunc("brightness", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return brightnessRange, cobra.ShellCompDirectiveKeepOrder | cobra.ShellCompDirectiveNoFileComp
 	})
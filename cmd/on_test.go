@@ -29,13 +29,54 @@ func Test_newCmdOn(t *testing.T) {
 
 	var tests = map[string]cmdTest{
 		"turn on": {
-			"on light.bedroom_main",
-			"(?m)^.*bedroom_main on",
+			"on light.livingroom_main",
+			`(?m)^.*Living Main \(livingroom_main\) on`,
+			"",
+		},
+		"turn on already on": {
+			"on light.livingroom_corner --retries 5",
+			"(?m)^.*livingroom_corner: hub reported no state change",
 			"",
 		},
 		"turn on multiple": {
-			"on light.bedroom_main light.bedroom_other",
-			"(?s).*bedroom_main on.*bedroom_other on",
+			"on switch.livingroom_warp switch.bedroom_warp",
+			`(?s).*livingroom_warp\) on.*bedroom_warp\) on`,
+			"",
+		},
+		"turn on and wait": {
+			"on light.livingroom_other --wait=2s",
+			`(?m)^.*livingroom_other\) on`,
+			"",
+		},
+		"turn on with brightness and wait": {
+			"on light.bedroom_other -b 20 --wait",
+			`(?m)^.*bedroom_other\) on`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_onWaitNotOn(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 turn off": {
+			"off media_player.player2",
+			`(?m)^.*player2\) off`,
+			"",
+		},
+		// media players are idle when turned on, so waiting for on would time out
+		"2 turn on and wait": {
+			"on media_player.player2 --wait=2s",
+			`(?m)^.*player2\) idle`,
 			"",
 		},
 	}
@@ -0,0 +1,67 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_openHAB(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.type", "openhab"); err != nil {
+		t.Error(err)
+	}
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"list entities of domain cover": {
+			"list entities -d cover",
+			`^.*States.*\n.*cover.*\n.*Office_Shutter`,
+			"",
+		},
+		"turn on": {
+			"on Garden_Pump",
+			`(?m)^.*Garden Pump \(Garden_Pump\) on`,
+			"",
+		},
+		"turn off and wait": {
+			"off switch.Garden_Pump --wait=2s",
+			`(?m)^.*Garden Pump \(Garden_Pump\) off`,
+			"",
+		},
+		"toggle": {
+			"toggle Livingroom_Light",
+			`(?m)^.*Livingroom Light \(Livingroom_Light\) on`,
+			"",
+		},
+		"brightness": {
+			"brightness Kitchen_Dimmer 30 --wait",
+			`(?m)^.*Kitchen Dimmer \(Kitchen_Dimmer\) brightness set to 30%`,
+			"",
+		},
+		"temperature": {
+			"temperature Heating_Setpoint 19.5",
+			`(?m)^.*Heating Setpoint \(Heating_Setpoint\) temperature set to 19.5`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_outputFormats(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"get json": {
+			"get light.bedroom_main -o json",
+			`^\{"entity_id":"light.bedroom_main","state":"on","attributes":\{.*"brightness":207.*\},"last_changed":"[^"]+","last_updated":"[^"]+"\}\n$`,
+			"",
+		},
+		"get yaml": {
+			"get climate.heating -o yaml",
+			`(?s)^---\n.*entity_id: climate.heating\n.*state: heat\n`,
+			"",
+		},
+		"get go-template": {
+			"get light.bedroom_main -o go-template={{.state}}:{{.attributes.brightness}}",
+			`^on:207\n$`,
+			"",
+		},
+		"action json": {
+			"on light.livingroom_main -o json",
+			`^\{"entity_id":"light.livingroom_main","state":"on","result":"success"\}\n$`,
+			"",
+		},
+		"action unchanged json": {
+			"on light.livingroom_corner -o json",
+			`^\{"entity_id":"light.livingroom_corner","result":"unchanged","message":"hub reported no state change"\}\n$`,
+			"",
+		},
+		"action tsv": {
+			"off switch.livingroom_warp switch.bedroom_warp -o tsv",
+			"^entity_id\tstate\tattributes\taction\tresult\tmessage\terror\n" +
+				"switch.livingroom_warp\t\t\t\tunchanged\thub reported no state change\t\n" +
+				"switch.bedroom_warp\t\t\t\tunchanged\thub reported no state change\t\n$",
+			"",
+		},
+		"list entities tsv": {
+			"list entities -d media_player -o tsv",
+			"^entity_id\tstate\tattributes\tlast_changed\tlast_updated\n" +
+				"media_player.player1\ton\t\\{.*\\}\t.*\n" +
+				"media_player.player2\ton\t\\{.*\\}\t.*\n$",
+			"",
+		},
+		"list services csv": {
+			"list services -d light -s turn_on -o csv",
+			"^domain,service,name,description\n" +
+				"light,turn_on,[^\n]*\n$",
+			"",
+		},
+		"list services json": {
+			"list services -d light -s turn_on -o json",
+			`^\{"domain":"light","service":"turn_on","name":"[^"]*","description":"[^"]*"\}\n$`,
+			"",
+		},
+		"config get go-template": {
+			"config get hub.type -o go-template={{.option}}={{.value}}",
+			`^hub.type=hass\n$`,
+			"",
+		},
+		"version yaml": {
+			"version -o yaml",
+			"^---\nversion: dev\ncommit: dev\ndate: \"1970-01-01\"\n$",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -30,7 +30,7 @@ func Test_newCmdPlay(t *testing.T) {
 	var tests = map[string]cmdTest{
 		"play mp3": {
 			"play player1 testdata/test.fake.mp3",
-			"(?m)^.*player1 playing test.fake.mp3",
+			`(?m)^.*Player 1 \(player1\) playing test.fake.mp3`,
 			"",
 		},
 	}
@@ -0,0 +1,54 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+)
+
+const (
+	// editorconfig-checker-disable
+	pressExample = `
+  # Press a button (short names, device_map and fuzzy matching work as usual)
+  hctl press restart_router
+
+  # Press a protected button without confirmation
+  hctl press garage_opener --yes
+  `
+	// editorconfig-checker-enable
+)
+
+func newPressCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	a := serviceAction{domains: []string{"button", "input_button"}, service: "press", verb: "press", done: "pressed"}
+
+	cmd := &cobra.Command{
+		Use:     "press BUTTON... [-y|--yes]",
+		Short:   "Press a button",
+		Example: pressExample,
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h, a.domains...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			a.run(h, out, args)
+		},
+	}
+
+	return cmd
+}
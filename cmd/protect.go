@@ -0,0 +1,102 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// serviceAction is a service call on entities of domains
+type serviceAction struct {
+	domains []string
+	service string
+	// verb is used in the usage (e.g. unlock), done in the result (e.g. unlocked)
+	verb string
+	done string
+	data map[string]any
+}
+
+// run resolves each name to an entity of the domains and calls the service for it
+func (a serviceAction) run(h *pkg.Hctl, out io.Writer, names []string) {
+	var hasErr bool
+	for _, name := range names {
+		entityID, err := h.ResolveInDomains(name, a.domains...)
+		if err != nil {
+			o.FprintErrorMsg(out, err)
+			hasErr = true
+			continue
+		}
+		results, err := h.CallEntity(entityID, a.service, a.data)
+		if err != nil {
+			o.FprintErrorMsg(out, err)
+			hasErr = true
+			continue
+		}
+		o.FprintResult(out, entityID, a.done, results)
+	}
+	if hasErr {
+		os.Exit(1)
+	}
+}
+
+// confirmProtected returns the confirmation of service calls for protected entities of cmd,
+// asking on stdin unless yes (--yes) is set
+func confirmProtected(cmd *cobra.Command, yes *bool) rest.ConfirmFunc {
+	var in *bufio.Reader
+	return func(entityID string, service string) bool {
+		if *yes {
+			return true
+		}
+		if in == nil {
+			// one reader for all questions, so no answer is lost in its buffer
+			in = bufio.NewReader(cmd.InOrStdin())
+		}
+		return confirm(in, cmd.ErrOrStderr(), fmt.Sprintf("%s is protected, really call %s?", entityID, service))
+	}
+}
+
+// confirm asks question on prompt and returns true if the answer read from in is yes
+// Nothing to read (e.g. no terminal) is no
+func confirm(in *bufio.Reader, prompt io.Writer, question string) bool {
+	fmt.Fprintf(prompt, "%s [y/N] ", question)
+	answer, err := in.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(prompt)
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// codeData returns the service data for an optional --code
+func codeData(code string) map[string]any {
+	if code == "" {
+		return nil
+	}
+	return map[string]any{"code": code}
+}
@@ -38,6 +38,11 @@ var (
 // rootCmd represents the base command when called without any subcommands
 func newRootCmd(h *pkg.Hctl, out io.Writer, _ []string) *cobra.Command {
 	var logLevel string
+	var retries int
+	var output string
+	var yes bool
+	// one Writer for all commands, so tsv/csv headers are only printed when needed
+	out = o.NewWriter(out)
 
 	banner, err := o.GetBanner()
 	if err != nil {
@@ -49,7 +54,20 @@ func newRootCmd(h *pkg.Hctl, out io.Writer, _ []string) *cobra.Command {
 		Use:   appName,
 		Short: "A command line tool to control your home automation",
 		Long:  fmt.Sprintf("%s\nHctl is a CLI tool to control your home automation", banner),
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := o.SetFormat(output); err != nil {
+				return err
+			}
+			if err := h.CheckContext(); err != nil {
+				return err
+			}
+			h.SetConfirm(confirmProtected(cmd, &yes))
+			if cmd.Flags().Changed("retries") {
+				if retries < 0 {
+					return fmt.Errorf("retries needs to be >= 0")
+				}
+				h.SetRetries(retries)
+			}
 			if logLevel != "" {
 				lvl, err := zerolog.ParseLevel(logLevel)
 				if err != nil {
@@ -62,21 +80,61 @@ func newRootCmd(h *pkg.Hctl, out io.Writer, _ []string) *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		newAlarmCmd(h, out),
+		newAutomationCmd(h, out),
 		newBrightnessCmd(h, out),
+		newCacheCmd(h, out),
+		newCalendarCmd(h, out),
+		newCallCmd(h, out),
 		newCompletionCmd(),
 		newConfigCmd(h, out),
+		newCoverCmd(h, out),
+		newDoctorCmd(h, out),
+		newEventCmd(h, out),
+		newFanCmd(h, out),
+		newGetCmd(h, out),
+		newHistoryCmd(h, out),
 		newInitCmd(h),
 		newListCmd(h, out),
+		newLockCmd(h, out),
+		newLogbookCmd(h, out),
+		newLogsCmd(h, out),
 		newOffCmd(h, out),
 		newOnCmd(h, out),
 		newPlayCmd(h, out),
+		newPressCmd(h, out),
+		newRunCmd(h, out),
+		newSceneCmd(h, out),
+		newSnapshotCmd(h, out),
 		newToggleCmd(h, out),
+		newUnlockCmd(h, out),
 		newVersionCmd(out),
 		newVolumeCmd(h, out),
 		newTemperatureCmd(h, out),
+		newTemplateCmd(h, out),
+		newWatchCmd(h, out),
 	)
 
 	cmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "", "Set the log level")
+	cmd.PersistentFlags().String("context", "", "Use the given context instead of current_context (env: HCTL_CONTEXT)")
+	h.BindContextFlag(cmd.PersistentFlags().Lookup("context"))
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", "", fmt.Sprintf("Output format (%s)", strings.Join(o.Formats, ", ")))
+	cmd.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation of protected entities")
+	cmd.PersistentFlags().IntVar(&retries, "retries", 0, "Retry idempotent requests on network and server errors (overrides retry.retries)")
+
+	err = cmd.RegisterFlagCompletionFunc("context", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return h.GetContexts(), cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		log.Error().Msgf("Could not register flag completion func for context: %+v", err)
+	}
+
+	err = cmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "yaml", "tsv", "csv", "go-template="}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
+	if err != nil {
+		log.Error().Msgf("Could not register flag completion func for output: %+v", err)
+	}
 
 	return cmd
 }
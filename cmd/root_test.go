@@ -17,9 +17,11 @@ package cmd
 import (
 	"bytes"
 	"io"
+	"maps"
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
@@ -34,6 +36,8 @@ type cmdTest struct {
 
 func newTestingHctl(t *testing.T) *pkg.Hctl {
 	t.Helper()
+	// keep completion cache of tests away from the users cache
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
 	h, err := pkg.NewHctl(true)
 	if err != nil {
 		t.Error(err)
@@ -59,9 +63,49 @@ func newTestingHctl(t *testing.T) *pkg.Hctl {
 	return h
 }
 
+func Test_protectedEntitiesConfig(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"entity ids": {
+			config: "protected_entities: [lock.front_door]\ncontexts:\n  lab:\n    protected_entities: [button.garage_opener]\n",
+		},
+		"short name": {
+			config:  "protected_entities: [front_door]\n",
+			wantErr: "protected_entities needs full entity ids (e.g. lock.front_door): front_door",
+		},
+		"device_map key in context": {
+			config:  "contexts:\n  lab:\n    device_map:\n      door: lock.front_door\n    protected_entities: [door]\n",
+			wantErr: "contexts.lab.protected_entities needs full entity ids (e.g. lock.front_door): door",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, err := pkg.NewHctl(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := path.Join(t.TempDir(), "hctl.yaml")
+			if err := os.WriteFile(file, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			err = h.LoadConfig(file)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got error %v, want none", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func testCmd(t *testing.T, h *pkg.Hctl, tests map[string]cmdTest) {
 	t.Helper()
-	for name, tt := range tests {
+	// run in a stable order, as the mock server keeps state between tests
+	for _, name := range slices.Sorted(maps.Keys(tests)) {
+		tt := tests[name]
 		t.Run(name, func(t *testing.T) {
 			out := new(bytes.Buffer)
 			errout := new(bytes.Buffer)
@@ -0,0 +1,68 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	runExample = `
+  # Run a script (short names, device_map and fuzzy matching work as usual)
+  hctl run morning_routine
+
+  # Run a script with variables, values are parsed as JSON if possible
+  hctl run morning_routine --var brightness=120 --var rooms='["kitchen","bath"]'
+  `
+	// editorconfig-checker-enable
+)
+
+func newRunCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var vars []string
+
+	cmd := &cobra.Command{
+		Use:     "run SCRIPT [--var key=value]...",
+		Short:   "Run a script",
+		Example: runExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return compListStates(toComplete, args, nil, nil, "", h, "script")
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			variables, err := parseData(vars, "")
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			entityID, results, err := h.RunScript(args[0], variables)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintResult(out, entityID, "started", results)
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVar(&vars, "var", []string{}, "Script variable as key=value, values are parsed as JSON if possible (e.g. brightness=120)")
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	sceneActivateExample = `
+  # Activate a scene of the hub
+  hctl scene activate working_from_home
+
+  # Activate a scene, fading lights over 3 seconds
+  hctl scene activate working_from_home --transition 3
+  `
+	// editorconfig-checker-enable
+)
+
+func newSceneActivateCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var transition float64
+
+	cmd := &cobra.Command{
+		Use:     "activate SCENE",
+		Short:   "Activate a scene of the hub",
+		Aliases: []string{"a", "on"},
+		Example: sceneActivateExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return compListStates(toComplete, args, nil, nil, "", h, "scene")
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			entityID, results, err := h.ActivateScene(args[0], transition)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			o.FprintResult(out, entityID, "activated", results)
+		},
+	}
+
+	cmd.PersistentFlags().Float64VarP(&transition, "transition", "s", 0, "Set transition time in seconds (e.g. 1.5)")
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	sceneRestoreExample = `
+  # Restore a local scene
+  hctl scene restore wfh
+
+  # Restore a local scene, fading lights over 2 seconds
+  hctl scene restore wfh --transition 2
+  `
+	// editorconfig-checker-enable
+)
+
+func newSceneRestoreCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var transition float64
+
+	cmd := &cobra.Command{
+		Use:     "restore NAME",
+		Short:   "Restore the states saved in a local scene",
+		Aliases: []string{"r"},
+		Example: sceneRestoreExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				names, _ := h.ListScenes()
+				return names, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			s, err := h.LoadScene(args[0])
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			var hasErr bool
+			for _, e := range s.Entities {
+				if !e.HasState() {
+					o.FprintWarning(out, fmt.Sprintf("Skipped %s, it was %s when the scene was saved", e.EntityID, e.State))
+					continue
+				}
+				results, err := h.RestoreEntity(e, transition)
+				if err != nil {
+					o.FprintErrorMsg(out, err)
+					hasErr = true
+					continue
+				}
+				o.FprintResult(out, e.EntityID, "restored", results)
+			}
+			if hasErr {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().Float64VarP(&transition, "transition", "s", 0, "Set transition time in seconds for lights (e.g. 1.5)")
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_sceneRestore(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	tests := map[string]cmdTest{
+		// runs in order of the names
+		"1 save": {
+			"scene save evening bedroom_main livingroom_corner player1",
+			"Saved 3 entities as scene evening",
+			"",
+		},
+		"2 change": {
+			"off bedroom_main livingroom_corner",
+			`(?s)bedroom_main\) off.*livingroom_corner\) off`,
+			"",
+		},
+		"3 restore": {
+			"scene restore evening --transition 1.5",
+			`(?s)Bedroom Main \(bedroom_main\) restored.*Living Corner \(livingroom_corner\) restored.*player1 restored: hub reported no state change`,
+			"",
+		},
+		"4 state": {
+			"get bedroom_main",
+			`(?s)light.bedroom_main\s+on\s.*brightness\s+207\s`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
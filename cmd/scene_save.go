@@ -0,0 +1,66 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/scene"
+)
+
+const (
+	// editorconfig-checker-disable
+	sceneSaveExample = `
+  # Save the current state of the living room lights and the player as local scene
+  hctl scene save wfh livingroom_main livingroom_corner player1
+  `
+	// editorconfig-checker-enable
+)
+
+func newSceneSaveCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "save NAME ENTITY...",
+		Short:   "Save the current state of entities as local scene",
+		Long:    fmt.Sprintf("Save the current state of entities as local scene\nSupported domains: %s", strings.Join(scene.Domains(), ", ")),
+		Aliases: []string{"s"},
+		Example: sceneSaveExample,
+		Args:    cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				names, _ := h.ListScenes()
+				return names, cobra.ShellCompDirectiveNoFileComp
+			}
+			return compListStatesMulti(toComplete, args[1:], nil, nil, "", h, scene.Domains()...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			s, err := h.SaveScene(args[0], args[1:])
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			for _, e := range s.Skipped {
+				o.FprintWarning(out, fmt.Sprintf("Skipped %s, it is %s", e.EntityID, e.State))
+			}
+			o.FprintSuccess(out, fmt.Sprintf("Saved %d entities as scene %s", len(s.Entities), s.Name))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+const (
+	// editorconfig-checker-disable
+	snapshotExample = `
+  # Save a snapshot of a camera (short names, device_map and fuzzy matching work as usual)
+  hctl snapshot front_door -f doorbell.jpg
+
+  # Save the current image of an image entity, or write it to stdout
+  hctl snapshot image.doorbell -f - | convert - -resize 50% small.jpg
+
+  # Save a frame every 10 seconds as timelapse_0001.jpg, timelapse_0002.jpg, ... until interrupted
+  hctl snapshot front_door -f timelapse.jpg --interval 10s
+
+  # Save 6 frames, one per minute (front_door_0001.jpg ... front_door_0006.jpg)
+  hctl snapshot front_door --interval 1m --count 6
+  `
+	// editorconfig-checker-enable
+)
+
+func newSnapshotCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var file string
+	var interval time.Duration
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "snapshot CAMERA [-f FILE] [--interval DURATION [--count N]]",
+		Short: "Save a snapshot of a camera or the image of an image entity",
+		Long: `Save a snapshot of a camera or the image of an image entity
+
+The image is saved to the file given with -f/--file, as -o/--output sets the format of the
+messages, like for all other commands`,
+		Example: snapshotExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return noMoreArgsComp()
+			}
+			return compListStates(toComplete, args, nil, nil, "", h, rest.ImageDomains...)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			if interval < 0 {
+				o.FprintError(out, fmt.Errorf("--interval must not be negative"))
+			}
+			if interval > 0 && file == "-" {
+				o.FprintError(out, fmt.Errorf("--interval needs a file, not stdout"))
+			}
+
+			if file == "-" && o.Structured() {
+				o.FprintError(out, fmt.Errorf("--output can't be used when writing the image to stdout"))
+			}
+
+			// resolve once, so all images of a series are of the same entity
+			entityID, err := h.ResolveInDomains(args[0], rest.ImageDomains...)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			if interval == 0 {
+				if err := saveSnapshot(h, out, entityID, file, 0); err != nil {
+					o.FprintError(out, err)
+				}
+				return
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			skipped, err := saveSeries(ctx, h, out, entityID, file, interval, count)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			if skipped > 0 {
+				o.FprintError(out, fmt.Errorf("%d frames skipped", skipped))
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&file, "file", "f", "", "File to save the image to, - for stdout (default ENTITY.jpg or ENTITY.png)")
+	cmd.PersistentFlags().DurationVar(&interval, "interval", 0, "Save a numbered series, one image per interval")
+	cmd.PersistentFlags().IntVar(&count, "count", 0, "Number of images to save with --interval (0 until interrupted)")
+
+	return cmd
+}
+
+// saveSeries saves count (0 until ctx is done) numbered images of entityID, one per interval
+// Frames failing with a transient error (e.g. the hub restarting) are skipped, other errors end the series
+// Returns the number of skipped frames
+func saveSeries(ctx context.Context, h *pkg.Hctl, out io.Writer, entityID string, file string, interval time.Duration, count int) (int, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var skipped int
+	for i := 1; count == 0 || i <= count; i++ {
+		if i > 1 {
+			select {
+			case <-ctx.Done():
+				return skipped, nil
+			case <-ticker.C:
+			}
+		}
+		if err := saveSnapshot(h, out, entityID, file, i); err != nil {
+			if !rest.Transient(err) {
+				return skipped, err
+			}
+			o.FprintErrorMsg(out, fmt.Errorf("frame %d skipped: %v", i, err))
+			skipped++
+		}
+	}
+	return skipped, nil
+}
+
+// saveSnapshot saves the image of entityID to file, or ENTITY.jpg (.png) if file is empty
+// If i > 0, the file is numbered as the i-th image of a series
+func saveSnapshot(h *pkg.Hctl, out io.Writer, entityID string, file string, i int) error {
+	entityID, img, err := h.Snapshot(entityID)
+	if err != nil {
+		return err
+	}
+	if file == "-" {
+		_, err := out.Write(img)
+		return err
+	}
+	if file == "" {
+		_, obj, _ := strings.Cut(entityID, ".")
+		file = obj + imageExt(img)
+	}
+	if i > 0 {
+		file = seriesName(file, i)
+	}
+	if err := os.WriteFile(file, img, 0o600); err != nil {
+		return err
+	}
+	o.FprintSuccess(out, fmt.Sprintf("Saved %s to %s", entityID, file))
+	return nil
+}
+
+// seriesName numbers file for the i-th image of a series, e.g. timelapse_0001.jpg
+func seriesName(file string, i int) string {
+	ext := filepath.Ext(file)
+	return fmt.Sprintf("%s_%04d%s", strings.TrimSuffix(file, ext), i, ext)
+}
+
+func imageExt(img []byte) string {
+	if http.DetectContentType(img) == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_snapshot(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	dir := t.TempDir()
+	tests := map[string]cmdTest{
+		"camera": {
+			"snapshot front_door -f " + path.Join(dir, "camera.jpg"),
+			"Saved camera.front_door to .*/camera.jpg",
+			"",
+		},
+		"image": {
+			"snapshot image.doorbell -f " + path.Join(dir, "image.png"),
+			"Saved image.doorbell to .*/image.png",
+			"",
+		},
+		"fuzzy camera": {
+			"snapshot frontdoor -f " + path.Join(dir, "fuzzy.jpg"),
+			"Saved camera.front_door to .*/fuzzy.jpg",
+			"",
+		},
+		"series": {
+			"snapshot camera.front_door -f " + path.Join(dir, "series.jpg") + " --interval 10ms --count 3",
+			"(?s)series_0001.jpg.*series_0002.jpg.*series_0003.jpg",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+		data, err := os.ReadFile(path.Join(dir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decode := jpeg.Decode
+		if path.Ext(f.Name()) == ".png" {
+			decode = png.Decode
+		}
+		if _, err := decode(bytes.NewReader(data)); err != nil {
+			t.Errorf("%s is no %s image: %v", f.Name(), path.Ext(f.Name()), err)
+		}
+	}
+	want := []string{"camera.jpg", "fuzzy.jpg", "image.png", "series_0001.jpg", "series_0002.jpg", "series_0003.jpg"}
+	if !slices.Equal(names, want) {
+		t.Errorf("got files %v, want %v", names, want)
+	}
+}
+
+func Test_snapshotStdout(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	args := []string{"snapshot", "doorbell", "-f", "-"}
+	out := new(bytes.Buffer)
+	rootCmd = newRootCmd(h, out, args)
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil {
+		t.Error(err)
+	}
+	if _, err := png.Decode(out); err != nil {
+		t.Errorf("no PNG on stdout: %v", err)
+	}
+}
+
+func Test_snapshotSeriesDefault(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+	t.Chdir(t.TempDir())
+
+	tests := map[string]cmdTest{
+		"camera": {
+			"snapshot frontdoor --interval 10ms --count 2",
+			"(?s)Saved camera.front_door to front_door_0001.jpg.*front_door_0002.jpg",
+			"",
+		},
+		"image": {
+			"snapshot doorbel --interval 10ms --count 2",
+			"(?s)Saved image.doorbell to doorbell_0001.png.*doorbell_0002.png",
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+
+	for _, file := range []string{"front_door_0001.jpg", "front_door_0002.jpg", "doorbell_0001.png", "doorbell_0002.png"} {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := image.Decode(f); err != nil {
+			t.Errorf("%s is no image: %v", file, err)
+		}
+		f.Close()
+	}
+}
+
+func Test_seriesName(t *testing.T) {
+	tests := map[string]struct {
+		file string
+		want string
+	}{
+		"with extension":    {"timelapse.jpg", "timelapse_0001.jpg"},
+		"without extension": {"out/frame", "out/frame_0001"},
+		"png":               {"doorbell.png", "doorbell_0001.png"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := seriesName(tt.file, 1); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_saveSeriesErrors(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	target, err := url.Parse(ms.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		status      int
+		wantFiles   []string
+		wantSkipped int
+		wantErr     bool
+	}{
+		"transient": {
+			status:      http.StatusServiceUnavailable,
+			wantFiles:   []string{"front_door_0001.jpg", "front_door_0003.jpg"},
+			wantSkipped: 1,
+		},
+		"permanent": {
+			status:    http.StatusUnauthorized,
+			wantFiles: []string{"front_door_0001.jpg"},
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// the second frame fails
+			var frames atomic.Int32
+			proxy := httputil.NewSingleHostReverseProxy(target)
+			ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "_proxy/") && frames.Add(1) == 2 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				proxy.ServeHTTP(w, r)
+			}))
+			defer ps.Close()
+
+			h := newTestingHctl(t)
+			if err := h.SetConfigValue("hub.url", ps.URL); err != nil {
+				t.Error(err)
+			}
+			h.SetRetries(0)
+			t.Chdir(t.TempDir())
+
+			skipped, err := saveSeries(context.Background(), h, new(bytes.Buffer), "camera.front_door", "", 10*time.Millisecond, 3)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("got %d skipped, want %d", skipped, tt.wantSkipped)
+			}
+			files, err := os.ReadDir(".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, f := range files {
+				names = append(names, f.Name())
+			}
+			if !slices.Equal(names, tt.wantFiles) {
+				t.Errorf("got files %v, want %v", names, tt.wantFiles)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package cmd
 import (
 	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -25,8 +26,10 @@ import (
 )
 
 func newTemperatureCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var wait time.Duration
+
 	cmd := &cobra.Command{
-		Use:     "temperature",
+		Use:     "temperature [--wait[=timeout]]",
 		Short:   "Set the temperature of a climate entity",
 		Aliases: []string{"te", "temp"}, // codespell:ignore
 		Args:    cobra.MatchAll(cobra.MinimumNArgs(2)),
@@ -42,12 +45,12 @@ func newTemperatureCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 			devices := args[:len(args)-1]
 			var hasErr bool
 			for _, device := range devices {
-				obj, state, err := h.TemperatureSet(device, value)
+				obj, state, results, err := h.TemperatureSet(device, value, wait)
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccessAction(out, obj, state)
+					o.FprintResult(out, obj, state, results)
 				}
 			}
 			if hasErr {
@@ -56,5 +59,7 @@ func newTemperatureCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 		},
 	}
 
+	addWaitFlag(cmd, &wait)
+
 	return cmd
 }
@@ -28,9 +28,14 @@ func Test_newCmdTemperature(t *testing.T) {
 	}
 
 	var tests = map[string]cmdTest{
-		"set volume": {
+		"set temperature": {
 			"temperature climate.heating 21.5",
-			"(?m)^.*heating temperature set to 21.5",
+			`(?m)^.*Heating \(heating\) temperature set to 21.5`,
+			"",
+		},
+		"set temperature and wait": {
+			"temperature climate.heating 19.25 --wait=2s",
+			`(?m)^.*heating\) temperature set to 19.2`,
 			"",
 		},
 	}
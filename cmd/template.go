@@ -0,0 +1,108 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	templateExample = `
+  # Render a template on the hub
+  hctl template '{{ states("sensor.outdoor_temperature") }}'
+
+  # Render a template file, or read the template from stdin
+  hctl template -f sensor.j2
+  echo '{{ area_entities("kitchen") | join(" ") }}' | hctl template
+
+  # Use the result in scripts
+  for light in $(hctl template '{{ states.light | selectattr("state", "eq", "on") | map(attribute="entity_id") | join(" ") }}'); do
+    hctl off "$light"
+  done
+  `
+	// editorconfig-checker-enable
+)
+
+func newTemplateCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:     "template [TEMPLATE|-] [-f FILE]",
+		Short:   "Render a Jinja template on the hub",
+		Aliases: []string{"tpl"},
+		Example: templateExample,
+		Args:    cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return noMoreArgsComp()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			tpl, err := readTemplate(cmd.InOrStdin(), args, file)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			res, err := h.RenderTemplate(tpl)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+			if o.Structured() {
+				if err := o.FprintRecord(out, o.Row{Keys: []string{"result"}, Values: []any{res}}); err != nil {
+					o.FprintError(out, err)
+				}
+				return
+			}
+			fmt.Fprint(out, res)
+			if !strings.HasSuffix(res, "\n") {
+				fmt.Fprintln(out)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&file, "file", "f", "", "Read the template from a file (- for stdin)")
+
+	return cmd
+}
+
+// readTemplate returns the template given as argument, in file or on stdin (no argument, or -)
+func readTemplate(stdin io.Reader, args []string, file string) (string, error) {
+	if len(args) > 0 && file != "" {
+		return "", fmt.Errorf("either give the template as argument or with --file, not both")
+	}
+	var data []byte
+	var err error
+	switch {
+	case len(args) > 0 && args[0] != "-":
+		return args[0], nil
+	case file != "" && file != "-":
+		data, err = os.ReadFile(file)
+	default:
+		data, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("empty template")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_template(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	file := path.Join(t.TempDir(), "sensor.j2")
+	if err := os.WriteFile(file, []byte("Garage door is {{ states('binary_sensor.garage_door') }}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]cmdTest{
+		"argument": {
+			`template {{states("sensor.outdoor_temperature")}}°C`,
+			"^14.2°C\n$",
+			"",
+		},
+		"file": {
+			"template -f " + file,
+			"^Garage door is off\n$",
+			"",
+		},
+		"json": {
+			`template {{states("climate.heating")}} -o json`,
+			`^\{"result":"heat"\}\n$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
+
+func Test_templateStdin(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	for _, args := range [][]string{{"template"}, {"template", "-"}, {"template", "-f", "-"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			out := new(bytes.Buffer)
+			rootCmd = newRootCmd(h, out, args)
+			rootCmd.SetIn(strings.NewReader(`{{ states("light.bedroom_main") }}`))
+			rootCmd.SetOut(out)
+			rootCmd.SetArgs(args)
+			if err := rootCmd.Execute(); err != nil {
+				t.Error(err)
+			}
+			if got := out.String(); got != "on\n" {
+				t.Errorf("got %q, want %q", got, "on\n")
+			}
+		})
+	}
+}
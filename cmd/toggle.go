@@ -15,8 +15,10 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -27,8 +29,10 @@ import (
 
 // toggleCmd represents the toggle command
 func newToggleCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var wait time.Duration
+
 	cmd := &cobra.Command{
-		Use:     "toggle",
+		Use:     "toggle [--wait[=timeout]]",
 		Short:   "Toggle on/off a light or switch",
 		Aliases: []string{"t"},
 		Args:    cobra.MatchAll(cobra.MinimumNArgs(1)),
@@ -36,15 +40,18 @@ func newToggleCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 			return compListStatesMulti(toComplete, args, []string{"toggle"}, nil, "", h)
 		},
 		Run: func(_ *cobra.Command, args []string) {
-			c := h.GetRest()
+			c := h.GetBackend()
 			var hasErr bool
 			for _, device := range args {
-				obj, state, sub, err := c.Toggle(device)
+				obj, state, sub, results, err := c.Toggle(device)
+				if err == nil {
+					results, err = waitForTarget(c, wait, results)
+				}
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccessAction(out, obj, state)
+					o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), "", results)
 				}
 				log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 			}
@@ -54,5 +61,7 @@ func newToggleCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 		},
 	}
 
+	addWaitFlag(cmd, &wait)
+
 	return cmd
 }
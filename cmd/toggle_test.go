@@ -30,12 +30,17 @@ func Test_newCmdToggle(t *testing.T) {
 	var tests = map[string]cmdTest{
 		"toggle light": {
 			"toggle bedroom_main",
-			"(?m)^.*bedroom_main toggle",
+			`(?m)^.*Bedroom Main \(bedroom_main\) off`,
 			"",
 		},
 		"toggle multiple lights": {
-			"toggle light.bedroom_main light.bedroom_other",
-			"(?s).*bedroom_main toggle.*bedroom_other toggle",
+			"toggle light.livingroom_main light.livingroom_other",
+			`(?s).*livingroom_main\) on.*livingroom_other\) on`,
+			"",
+		},
+		"toggle and wait": {
+			"toggle light.bedroom_other --wait=2s",
+			`(?m)^.*bedroom_other\) off`,
 			"",
 		},
 	}
@@ -42,6 +42,13 @@ func newVersionCmd(out io.Writer) *cobra.Command {
 }
 
 func printVersion(out io.Writer, short bool) {
+	if o.Structured() {
+		r := o.Row{Keys: []string{"version", "commit", "date"}, Values: []any{version, commit, date}}
+		if err := o.FprintRecord(out, r); err != nil {
+			o.FprintError(out, err)
+		}
+		return
+	}
 	if !short {
 		banner, err := o.GetBanner()
 		if err != nil {
@@ -19,6 +19,7 @@ import (
 	"io"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -30,8 +31,9 @@ import (
 // toggleCmd represents the toggle command
 func newVolumeCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 	volRange := util.MakeRangeString(0, 100)
+	var wait time.Duration
 	cmd := &cobra.Command{
-		Use:     "volume",
+		Use:     "volume [--wait[=timeout]]",
 		Short:   "Set volume of e.g media player",
 		Aliases: []string{"v"},
 		Args:    cobra.MatchAll(cobra.MinimumNArgs(2)),
@@ -51,12 +53,12 @@ func newVolumeCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 			}
 			var hasErr bool
 			for _, device := range devices {
-				obj, state, err := h.VolumeSet(device, value)
+				obj, state, results, err := h.VolumeSet(device, value, wait)
 				if err != nil {
 					o.FprintErrorMsg(out, err)
 					hasErr = true
 				} else {
-					o.FprintSuccessAction(out, obj, state)
+					o.FprintResult(out, obj, state, results)
 				}
 			}
 			if hasErr {
@@ -65,5 +67,7 @@ func newVolumeCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
 		},
 	}
 
+	addWaitFlag(cmd, &wait)
+
 	return cmd
 }
@@ -30,7 +30,22 @@ func Test_newCmdVolume(t *testing.T) {
 	var tests = map[string]cmdTest{
 		"set volume": {
 			"volume media_player.player1 10",
-			"(?m)^.*player1 volume set to 10%",
+			`(?m)^.*Player 1 \(player1\) volume set to 10%`,
+			"",
+		},
+		"set volume and wait": {
+			"volume media_player.player1 35 --wait=2s",
+			`(?m)^.*player1\) volume set to 35%`,
+			"",
+		},
+		"set volume unchanged": {
+			"volume media_player.player2 0",
+			"(?m)^.*player2 volume set to 0%: hub reported no state change",
+			"",
+		},
+		"set volume unchanged and wait": {
+			"volume media_player.player2 0 --wait=2s",
+			`(?m)^.*Player 2 \(player2\) volume set to 0%`,
 			"",
 		},
 	}
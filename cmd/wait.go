@@ -0,0 +1,45 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg/backend"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+const defaultWaitTimeout = 30 * time.Second
+
+// addWaitFlag adds `--wait[=timeout]` to cmd
+func addWaitFlag(cmd *cobra.Command, wait *time.Duration) {
+	cmd.PersistentFlags().DurationVar(wait, "wait", 0, "Wait until the target state is reached, with optional timeout (e.g. --wait=10s)")
+	cmd.PersistentFlags().Lookup("wait").NoOptDefVal = defaultWaitTimeout.String()
+}
+
+// waitForTarget blocks until the last action of c reached its target, if wait is set
+// Returns results of the action, updated with the state confirmed by waiting
+func waitForTarget(c backend.Backend, wait time.Duration, results []rest.HassResult) ([]rest.HassResult, error) {
+	if wait <= 0 {
+		return results, nil
+	}
+	r, err := c.Wait(wait)
+	if err != nil {
+		return nil, err
+	}
+	return rest.MergeResult(results, r), nil
+}
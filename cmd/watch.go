@@ -0,0 +1,78 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/xx4h/hctl/pkg"
+	o "github.com/xx4h/hctl/pkg/output"
+)
+
+const (
+	// editorconfig-checker-disable
+	watchExample = `
+  # Watch all state changes
+  hctl watch
+
+  # Watch a single entity (short names, device_map and fuzzy matching work as usual)
+  hctl watch bedroom_main
+
+  # Watch all lights and everything named like "*kitchen*"
+  hctl watch -d light '*kitchen*'
+
+  # Print changes as JSON lines and exit after the first 10
+  hctl watch 'sensor.temp_*' -o json --count 10
+  `
+	// editorconfig-checker-enable
+)
+
+func newWatchCmd(h *pkg.Hctl, out io.Writer) *cobra.Command {
+	var domains []string
+	var count int
+
+	cmd := &cobra.Command{
+		Use:     "watch [ENTITY|GLOB...]",
+		Short:   "Watch live state changes",
+		Aliases: []string{"w"},
+		Example: watchExample,
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListStatesMulti(toComplete, args, nil, nil, "", h)
+		},
+		Run: func(_ *cobra.Command, args []string) {
+			filter, err := h.NewWatchFilter(args, domains)
+			if err != nil {
+				o.FprintError(out, err)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			if err := h.Watch(ctx, out, filter, count); err != nil {
+				o.FprintError(out, err)
+			}
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVarP(&domains, "domains", "d", []string{}, "Limit domains")
+	cmd.PersistentFlags().IntVarP(&count, "count", "n", 0, "Exit after n changes")
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_newCmdWatch(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	h := newTestingHctl(t)
+	if err := h.SetConfigValue("hub.url", ms.URL); err != nil {
+		t.Error(err)
+	}
+
+	var tests = map[string]cmdTest{
+		"watch all": {
+			"watch --count 3",
+			`(?s)light\.bedroom_main\s+on → off.*switch\.livingroom_warp\s+off → on.*media_player\.player1\s+on → on  volume_level: 0.5 → 0.3`,
+			"",
+		},
+		"watch entity": {
+			"watch player1 --count 1",
+			`(?m)^.*media_player\.player1\s+on → on  volume_level: 0.5 → 0.3$`,
+			"",
+		},
+		"watch domain": {
+			"watch -d switch --count 1",
+			`(?m)^.*switch\.livingroom_warp\s+off → on$`,
+			"",
+		},
+		"watch glob": {
+			"watch *_warp --count 1",
+			`(?m)^.*switch\.livingroom_warp\s+off → on$`,
+			"",
+		},
+		"watch json": {
+			"watch light.bedroom_* -o json --count 1",
+			`(?m)^\{"time_fired":"2024-10-12T18:30:02.10203Z","entity_id":"light.bedroom_main","old_state":"on","new_state":"off","changed_attributes":\{"color_mode":\{"old":"onoff","new":null\}\}\}$`,
+			"",
+		},
+	}
+
+	testCmd(t, h, tests)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import "github.com/xx4h/hctl/pkg/rest"
+
+// callInDomain resolves name (short, mapped or fuzzy) to an entity of domain and calls domain.service for it
+// Returns the entity id
+func (h *Hctl) callInDomain(domain string, name string, service string, data map[string]any) (string, []rest.HassResult, error) {
+	entityID, err := h.ResolveInDomains(name, domain)
+	if err != nil {
+		return "", nil, err
+	}
+	results, err := h.CallEntity(entityID, service, data)
+	return entityID, results, err
+}
+
+// RunScript starts a script with variables (may be empty), the script keeps running on the hub
+// Returns the entity id of the script
+func (h *Hctl) RunScript(name string, variables map[string]any) (string, []rest.HassResult, error) {
+	var data map[string]any
+	if len(variables) > 0 {
+		data = map[string]any{"variables": variables}
+	}
+	return h.callInDomain("script", name, "turn_on", data)
+}
+
+// TriggerAutomation runs the actions of an automation, skipping its conditions if skipCondition is set
+// Returns the entity id of the automation
+func (h *Hctl) TriggerAutomation(name string, skipCondition bool) (string, []rest.HassResult, error) {
+	return h.callInDomain("automation", name, "trigger", map[string]any{"skip_condition": skipCondition})
+}
+
+// EnableAutomation enables or disables an automation
+// Returns the entity id of the automation
+func (h *Hctl) EnableAutomation(name string, enable bool) (string, []rest.HassResult, error) {
+	service := "turn_off"
+	if enable {
+		service = "turn_on"
+	}
+	return h.callInDomain("automation", name, service, nil)
+}
+
+// GetAutomations returns the states of all automations
+func (h *Hctl) GetAutomations() ([]rest.HassState, error) {
+	c, err := h.GetHass("automation")
+	if err != nil {
+		return nil, err
+	}
+	return c.GetFilteredStates([]string{"automation"})
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backend abstracts the hub hctl talks to
+// States, services and results of all backends use the Home Assistant model
+// of the rest package, so commands, completion and output don't have to care
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/config"
+	"github.com/xx4h/hctl/pkg/openhab"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// Backend is implemented by all supported hub types (hub.type)
+// Actions return obj, state, domain, the states reported by the hub and an error
+type Backend interface {
+	// entity listing
+	GetStates() ([]rest.HassState, error)
+	GetFilteredStates(domains []string) ([]rest.HassState, error)
+	GetFilteredStatesMap(domains []string) (map[string][]string, error)
+	ResolveEntity(name string) (string, string, error)
+	// FetchState gets a single state directly from the hub, bypassing cached states
+	FetchState(domain, name string) (rest.HassState, error)
+
+	// service discovery
+	GetServices() ([]rest.HassService, error)
+	GetFilteredServices(domains []string, services []string) ([]rest.HassService, error)
+	GetFilteredServicesMap(domains []string, services []string) (map[string][]string, error)
+
+	// actions
+	TurnOn(args ...string) (string, string, string, []rest.HassResult, error)
+	TurnOff(args ...string) (string, string, string, []rest.HassResult, error)
+	Toggle(args ...string) (string, string, string, []rest.HassResult, error)
+	TurnLightOnCustom(device, brightness, color string, colorTemp int, transition float64) (string, string, string, []rest.HassResult, error)
+	TurnLightOffTransition(device string, transition float64) (string, string, string, []rest.HassResult, error)
+	VolumeSet(obj string, volume int) (string, string, string, []rest.HassResult, error)
+	TemperatureSet(obj string, temp float64) (string, string, string, []rest.HassResult, error)
+	PlayMusic(obj, mediaURL, name string) (string, string, string, []rest.HassResult, error)
+	// CallService calls any service of domain, e.g. light.turn_on, and returns the changed states
+	CallService(domain string, service string, entities []string, data map[string]any) ([]rest.HassResult, error)
+
+	// Wait blocks until the last action reached its target
+	Wait(timeout time.Duration) (rest.HassResult, error)
+	// UseCache makes the backend serve states and services from the completion cache
+	UseCache(states []rest.HassState, services []rest.HassService)
+}
+
+var (
+	_ Backend = (*rest.Hass)(nil)
+	_ Backend = (*openhab.OpenHAB)(nil)
+)
+
+// Options are the settings shared by all backends
+type Options struct {
+	Fuzz      bool
+	DeviceMap map[string]string
+	Protected []string
+	Confirm   rest.ConfirmFunc
+	Client    *http.Client
+	Retry     rest.Retry
+}
+
+// New returns the backend for hub.Type
+func New(hub *config.Hub, opts Options) (Backend, error) {
+	switch hub.Type {
+	case "", "hass":
+		r := rest.New(hub.URL, hub.Token, opts.Fuzz, opts.DeviceMap)
+		r.Protected = opts.Protected
+		r.Confirm = opts.Confirm
+		r.Client = opts.Client
+		r.Retry = opts.Retry
+		return r, nil
+	case "openhab":
+		oh := openhab.New(hub.URL, hub.Token, opts.Fuzz, opts.DeviceMap)
+		oh.Protected = opts.Protected
+		oh.Confirm = opts.Confirm
+		oh.Client = opts.Client
+		oh.Retry = opts.Retry
+		return oh, nil
+	}
+	return nil, fmt.Errorf("unknown hub type: %s", hub.Type)
+}
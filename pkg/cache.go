@@ -0,0 +1,160 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/backend"
+	"github.com/xx4h/hctl/pkg/cache"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+const (
+	cacheKeyStates   = "states"
+	cacheKeyServices = "services"
+)
+
+// refreshCacheInBackground starts `hctl cache refresh` as separate process,
+// so completion does not have to wait for the hub
+var refreshCacheInBackground = func(c *cache.Cache, context string, configFile string) {
+	if !c.TryLock() {
+		log.Debug().Caller().Msg("Cache refresh already running")
+		return
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		c.Unlock()
+		return
+	}
+	// the child takes over the lock and releases it when done
+	args := []string{"cache", "refresh", "--locked"}
+	if context != "" {
+		args = append(args, "--context", context)
+	}
+	//nolint:gosec
+	cmd := exec.Command(exe, args...)
+	if configFile != "" {
+		cmd.Env = append(os.Environ(), "HCTL_CONFIG="+configFile)
+	}
+	if err := cmd.Start(); err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		c.Unlock()
+		return
+	}
+	log.Debug().Caller().Msgf("Started cache refresh in background (pid %d)", cmd.Process.Pid)
+	if err := cmd.Process.Release(); err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+}
+
+func (h *Hctl) getCache() (*cache.Cache, error) {
+	dir, err := cache.DefaultDir(h.cfg.GetHub().URL)
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir, h.cfg.GetCacheTTL()), nil
+}
+
+// storeCache fetches states and services from the hub and stores them in the cache
+// Returns the fetched states and services
+func storeCache(c *cache.Cache, r backend.Backend) ([]rest.HassState, []rest.HassService, error) {
+	states, err := r.GetStates()
+	if err != nil {
+		return nil, nil, err
+	}
+	services, err := r.GetServices()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Store(cacheKeyStates, states); err != nil {
+		return nil, nil, err
+	}
+	return states, services, c.Store(cacheKeyServices, services)
+}
+
+// GetCachedBackend returns a backend with states and services from the on-disk cache
+// Stale entries are used anyway and refreshed in background (stale-while-revalidate)
+func (h *Hctl) GetCachedBackend() backend.Backend {
+	r := h.GetBackend()
+	if h.cfg.GetCacheTTL() == 0 {
+		return r
+	}
+
+	c, err := h.getCache()
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		return r
+	}
+
+	var states []rest.HassState
+	var services []rest.HassService
+	statesFound, statesFresh, err := c.Load(cacheKeyStates, &states)
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+	servicesFound, servicesFresh, err := c.Load(cacheKeyServices, &services)
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+	}
+
+	if !statesFound || !servicesFound {
+		// nothing to serve yet, so we have to fetch once
+		states, services, err := storeCache(c, r)
+		if err != nil {
+			log.Debug().Caller().Msgf("Error: %+v", err)
+			return r
+		}
+		r.UseCache(states, services)
+		return r
+	}
+
+	r.UseCache(states, services)
+	if !statesFresh || !servicesFresh {
+		refreshCacheInBackground(c, h.cfg.GetContext(), h.cfg.Viper.ConfigFileUsed())
+	}
+	return r
+}
+
+// RefreshCache fetches states and services from the hub and updates the cache
+// If locked is set, the caller already holds the refresh lock (e.g. taken by the
+// process starting a background refresh), otherwise it is acquired here
+// Either way the lock is released when done
+func (h *Hctl) RefreshCache(locked bool) error {
+	c, err := h.getCache()
+	if err != nil {
+		return err
+	}
+	if !locked && !c.TryLock() {
+		return fmt.Errorf("cache refresh already running")
+	}
+	defer c.Unlock()
+	_, _, err = storeCache(c, h.GetBackend())
+	return err
+}
+
+// ClearCache removes all cached entries of the current hub
+func (h *Hctl) ClearCache() error {
+	c, err := h.getCache()
+	if err != nil {
+		return err
+	}
+	return c.Clear()
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+const (
+	lockFile = "refresh.lock"
+	// a refresh lock older than this is considered abandoned
+	lockTimeout = time.Minute
+)
+
+// Cache is an on-disk cache for hub payloads (e.g. states and services)
+type Cache struct {
+	Dir string
+	TTL time.Duration
+}
+
+// DefaultDir returns the cache directory for the hub with the given url
+// e.g. ~/.cache/hctl/<hash>
+func DefaultDir(hubURL string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "hctl", util.GetStringHash(hubURL)[:16]), nil
+}
+
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Load reads key into v
+// found is false if there is no cached entry, fresh is false if the entry is older than TTL
+func (c *Cache) Load(key string, v any) (bool, bool, error) {
+	p := c.path(key)
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, false, nil
+	} else if err != nil {
+		return false, false, err
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return false, false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		// treat broken cache entries like missing ones
+		log.Debug().Caller().Msgf("Could not read cache entry %s: %+v", p, err)
+		return false, false, nil
+	}
+
+	fresh := time.Since(info.ModTime()) < c.TTL
+	log.Debug().Caller().Msgf("Loaded cache entry %s (fresh: %t)", p, fresh)
+	return true, fresh, nil
+}
+
+// Store writes v to key
+func (c *Cache) Store(key string, v any) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// write to temp file first, so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Clear removes all cached entries
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// TryLock acquires the refresh lock, returns false if a refresh is already running
+func (c *Cache) TryLock() bool {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return false
+	}
+	p := filepath.Join(c.Dir, lockFile)
+	if info, err := os.Stat(p); err == nil && time.Since(info.ModTime()) > lockTimeout {
+		log.Debug().Caller().Msgf("Removing abandoned cache lock %s", p)
+		os.Remove(p)
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// Unlock releases the refresh lock
+func (c *Cache) Unlock() {
+	os.Remove(filepath.Join(c.Dir, lockFile))
+}
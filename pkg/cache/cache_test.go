@@ -0,0 +1,112 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_LoadStore(t *testing.T) {
+	var tests = map[string]struct {
+		ttl       time.Duration
+		store     bool
+		age       time.Duration
+		wantFound bool
+		wantFresh bool
+	}{
+		"miss":  {time.Minute, false, 0, false, false},
+		"fresh": {time.Minute, true, 0, true, true},
+		"stale": {time.Minute, true, 2 * time.Minute, true, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := New(t.TempDir(), tt.ttl)
+			want := []string{"light.bedroom_main", "switch.livingroom_warp"}
+			if tt.store {
+				if err := c.Store("states", want); err != nil {
+					t.Fatal(err)
+				}
+				old := time.Now().Add(-tt.age)
+				if err := os.Chtimes(c.path("states"), old, old); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			found, fresh, err := c.Load("states", &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound || fresh != tt.wantFresh {
+				t.Errorf("found/fresh = %t/%t, want %t/%t", found, fresh, tt.wantFound, tt.wantFresh)
+			}
+			if found && len(got) != len(want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_LoadBroken(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+	if err := os.WriteFile(c.path("states"), []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	found, _, err := c.Load("states", &got)
+	if err != nil || found {
+		t.Errorf("broken entry should be a miss, got found %t, err %v", found, err)
+	}
+}
+
+func Test_Clear(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "hub"), time.Minute)
+	if err := c.Store("states", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if found, _, _ := c.Load("states", &got); found {
+		t.Error("entry still found after Clear")
+	}
+}
+
+func Test_Lock(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+	if !c.TryLock() {
+		t.Fatal("could not acquire lock")
+	}
+	if c.TryLock() {
+		t.Error("lock acquired twice")
+	}
+	c.Unlock()
+	if !c.TryLock() {
+		t.Error("could not acquire lock after Unlock")
+	}
+
+	// abandoned lock
+	old := time.Now().Add(-2 * lockTimeout)
+	if err := os.Chtimes(filepath.Join(c.Dir, lockFile), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !c.TryLock() {
+		t.Error("abandoned lock not taken over")
+	}
+}
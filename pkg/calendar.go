@@ -0,0 +1,139 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+// NewCalendarEvent describes an event to add, Start and End are either dates (all-day
+// event) or anything util.ParseFutureTime takes
+type NewCalendarEvent struct {
+	Summary     string
+	Start       string
+	End         string
+	Description string
+	Location    string
+}
+
+// GetCalendars returns all calendar entities
+func (h *Hctl) GetCalendars() ([]rest.HassCalendar, error) {
+	c, err := h.GetHass("calendar")
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCalendars()
+}
+
+// GetCalendarEvents returns the events between from and to of all given calendars
+// (or all calendars, if none given), sorted by start
+func (h *Hctl) GetCalendarEvents(calendars []string, from time.Time, to time.Time) ([]rest.CalendarEvent, error) {
+	c, err := h.GetHass("calendar")
+	if err != nil {
+		return nil, err
+	}
+	if len(calendars) == 0 {
+		all, err := c.GetCalendars()
+		if err != nil {
+			return nil, err
+		}
+		for _, cal := range all {
+			calendars = append(calendars, cal.EntityID)
+		}
+	}
+
+	events := []rest.CalendarEvent{}
+	for _, cal := range calendars {
+		e, err := c.GetCalendarEvents(cal, from, to)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e...)
+	}
+	slices.SortStableFunc(events, func(a, b rest.CalendarEvent) int {
+		// unparsable times sort first, HA always sends valid ones
+		at, _ := a.Start.Time()
+		bt, _ := b.Start.Time()
+		return at.Compare(bt)
+	})
+	return events, nil
+}
+
+// AddCalendarEvent adds an event to calendar and returns the entity id of the calendar
+func (h *Hctl) AddCalendarEvent(calendar string, event NewCalendarEvent, now time.Time) (string, error) {
+	c, err := h.GetHass("calendar")
+	if err != nil {
+		return "", err
+	}
+	data, err := calendarEventData(event, now)
+	if err != nil {
+		return "", err
+	}
+	return c.CreateCalendarEvent(calendar, data)
+}
+
+// calendarEventData returns the service data for calendar.create_event
+// A start date without time creates an all-day event, ending after the day of the end date
+// (default: a single day), otherwise the event ends at End (default: an hour after start)
+func calendarEventData(event NewCalendarEvent, now time.Time) (map[string]any, error) {
+	if event.Summary == "" {
+		return nil, fmt.Errorf("summary must not be empty")
+	}
+	data := map[string]any{"summary": event.Summary}
+	if event.Description != "" {
+		data["description"] = event.Description
+	}
+	if event.Location != "" {
+		data["location"] = event.Location
+	}
+
+	if startDate, err := time.ParseInLocation(time.DateOnly, event.Start, time.Local); err == nil {
+		endDate := startDate
+		if event.End != "" {
+			if endDate, err = time.ParseInLocation(time.DateOnly, event.End, time.Local); err != nil {
+				return nil, fmt.Errorf("end of an all-day event has to be a date like 2006-01-02: %s", event.End)
+			}
+		}
+		if endDate.Before(startDate) {
+			return nil, fmt.Errorf("end %s is before start %s", event.End, event.Start)
+		}
+		// the end date is exclusive for HA
+		data["start_date"] = startDate.Format(time.DateOnly)
+		data["end_date"] = endDate.AddDate(0, 0, 1).Format(time.DateOnly)
+		return data, nil
+	}
+
+	start, err := util.ParseFutureTime(event.Start, now)
+	if err != nil {
+		return nil, err
+	}
+	end := start.Add(time.Hour)
+	if event.End != "" {
+		if end, err = util.ParseFutureTime(event.End, now); err != nil {
+			return nil, err
+		}
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end %s is not after start %s", end.Format(time.DateTime), start.Format(time.DateTime))
+	}
+	data["start_date_time"] = start.Format(time.RFC3339)
+	data["end_date_time"] = end.Format(time.RFC3339)
+	return data, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/config"
+)
+
+// TLSConfig returns the tls config for the given hub
+func TLSConfig(hub *config.Hub) (*tls.Config, error) {
+	//nolint:gosec
+	c := &tls.Config{InsecureSkipVerify: hub.TLS.InsecureSkipVerify}
+	if hub.TLS.InsecureSkipVerify {
+		log.Warn().Msg("TLS certificate verification of the hub is disabled")
+	}
+
+	if hub.TLS.CAFile != "" {
+		pem, err := os.ReadFile(hub.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read hub.tls.ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Debug().Caller().Msgf("Could not load system cert pool: %+v", err)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in hub.tls.ca_file: %s", hub.TLS.CAFile)
+		}
+		c.RootCAs = pool
+	}
+
+	if (hub.TLS.CertFile == "") != (hub.TLS.KeyFile == "") {
+		return nil, errors.New("hub.tls.cert_file and hub.tls.key_file need to be set together")
+	}
+	if hub.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(hub.TLS.CertFile, hub.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+
+	return c, nil
+}
+
+// Proxy returns the proxy func for the given hub, falling back to the
+// environment (HTTPS_PROXY, HTTP_PROXY, NO_PROXY) if no proxy is configured
+func Proxy(hub *config.Hub) (func(*http.Request) (*url.URL, error), error) {
+	if hub.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(hub.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hub.proxy: %w", err)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// Timeout returns hub.timeout as duration, 0 means no timeout
+func Timeout(hub *config.Hub) (time.Duration, error) {
+	if hub.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(hub.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hub.timeout: %w", err)
+	}
+	return d, nil
+}
+
+// New returns a http client for the given hub
+func New(hub *config.Hub) (*http.Client, error) {
+	tlsConfig, err := TLSConfig(hub)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := Proxy(hub)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := Timeout(hub)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = proxy
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// NewDialer returns a websocket dialer for the given hub
+func NewDialer(hub *config.Hub) (*websocket.Dialer, error) {
+	tlsConfig, err := TLSConfig(hub)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := Proxy(hub)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := Timeout(hub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &websocket.Dialer{
+		TLSClientConfig:  tlsConfig,
+		Proxy:            proxy,
+		HandshakeTimeout: timeout,
+	}, nil
+}
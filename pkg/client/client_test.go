@@ -0,0 +1,164 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/config"
+)
+
+func writeCA(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "ca.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func Test_New(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	ca := writeCA(t, ts)
+
+	var tests = map[string]struct {
+		hub config.Hub
+		err string
+	}{
+		"self-signed fails": {
+			config.Hub{},
+			"certificate signed by unknown authority",
+		},
+		"ca_file": {
+			config.Hub{TLS: config.TLS{CAFile: ca}},
+			"",
+		},
+		"insecure_skip_verify": {
+			config.Hub{TLS: config.TLS{InsecureSkipVerify: true}},
+			"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New(&tt.hub)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := c.Get(ts.URL)
+			if err == nil {
+				res.Body.Close()
+			}
+			if tt.err == "" && err != nil {
+				t.Errorf("got error %v, want none", err)
+			} else if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_NewErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte(""), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		hub config.Hub
+		err string
+	}{
+		"missing ca_file": {
+			config.Hub{TLS: config.TLS{CAFile: "/does/not/exist.pem"}},
+			"could not read hub.tls.ca_file",
+		},
+		"empty ca_file": {
+			config.Hub{TLS: config.TLS{CAFile: empty}},
+			"no certificates found in hub.tls.ca_file",
+		},
+		"cert without key": {
+			config.Hub{TLS: config.TLS{CertFile: empty}},
+			"hub.tls.cert_file and hub.tls.key_file need to be set together",
+		},
+		"invalid timeout": {
+			config.Hub{Timeout: "soon"},
+			"invalid hub.timeout",
+		},
+		"invalid proxy": {
+			config.Hub{Proxy: "http://[::1"},
+			"invalid hub.proxy",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(&tt.hub)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c, err := New(&config.Hub{Timeout: "50ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Get(ts.URL)
+	if err == nil {
+		res.Body.Close()
+		t.Fatal("request did not time out")
+	}
+}
+
+func Test_Proxy(t *testing.T) {
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		proxied = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	c, err := New(&config.Hub{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Get("http://home-assistant.example.com/api/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if !proxied {
+		t.Error("request was not sent through proxy")
+	}
+}
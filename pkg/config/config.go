@@ -25,9 +25,11 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -37,15 +39,36 @@ type Config struct {
 	Handling   Handling          `mapstructure:"handling" yaml:"handling" json:"handling"`
 	Logging    Logging           `mapstructure:"logging" yaml:"logging" json:"logging"`
 	Serve      Serve             `mapstructure:"serve" yaml:"serve" json:"serve"`
+	Retry      Retry             `mapstructure:"retry" yaml:"retry" json:"retry"`
 	DeviceMap  map[string]string `mapstructure:"device_map" yaml:"device_map" json:"device_map"`
 	MediaMap   map[string]string `mapstructure:"media_map" yaml:"media_map" json:"media_map"`
-	Viper      *viper.Viper
+	// entities that need confirmation (or --yes) for lock, alarm and button actions
+	ProtectedEntities []string `mapstructure:"protected_entities" yaml:"protected_entities" json:"protected_entities"`
+	// named hubs to switch between, see context.go
+	Contexts       map[string]Context `mapstructure:"contexts" yaml:"contexts" json:"contexts"`
+	CurrentContext string             `mapstructure:"current_context" yaml:"current_context" json:"current_context"`
+	Viper          *viper.Viper
+
+	contextFlag *pflag.Flag
 }
 
+// HubTypes lists the supported hub types, see pkg/backend
+var HubTypes = []string{"hass", "openhab"}
+
 type Hub struct {
-	Type  string `mapstructure:"type" yaml:"type" json:"type"`
-	URL   string `mapstructure:"url" yaml:"url" json:"url"`
-	Token string `mapstructure:"token" yaml:"token" json:"token"`
+	Type    string `mapstructure:"type" yaml:"type" json:"type"`
+	URL     string `mapstructure:"url" yaml:"url" json:"url"`
+	Token   string `mapstructure:"token" yaml:"token" json:"token"`
+	TLS     TLS    `mapstructure:"tls" yaml:"tls" json:"tls"`
+	Timeout string `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+	Proxy   string `mapstructure:"proxy" yaml:"proxy" json:"proxy"`
+}
+
+type TLS struct {
+	CAFile             string `mapstructure:"ca_file" yaml:"ca_file" json:"ca_file"`
+	CertFile           string `mapstructure:"cert_file" yaml:"cert_file" json:"cert_file"`
+	KeyFile            string `mapstructure:"key_file" yaml:"key_file" json:"key_file"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
 }
 
 type Completion struct {
@@ -53,7 +76,8 @@ type Completion struct {
 }
 
 type Handling struct {
-	Fuzz bool `mapstructure:"fuzz" yaml:"fuzz" json:"fuzz"`
+	Fuzz     bool   `mapstructure:"fuzz" yaml:"fuzz" json:"fuzz"`
+	CacheTTL string `mapstructure:"cache_ttl" yaml:"cache_ttl" json:"cache_ttl"`
 }
 
 type Logging struct {
@@ -65,6 +89,12 @@ type Serve struct {
 	Port int    `mapstructure:"port" yaml:"port" json:"port"`
 }
 
+type Retry struct {
+	Retries    int    `mapstructure:"retries" yaml:"retries" json:"retries"`
+	Backoff    string `mapstructure:"backoff" yaml:"backoff" json:"backoff"`
+	MaxBackoff string `mapstructure:"max_backoff" yaml:"max_backoff" json:"max_backoff"`
+}
+
 func NewViper() (*viper.Viper, error) {
 	userDir, err := os.UserHomeDir()
 	if err != nil {
@@ -87,6 +117,10 @@ func NewViper() (*viper.Viper, error) {
 	v.AddConfigPath(".")
 	v.AddConfigPath(path.Join(userDir, ".config/hctl"))
 	v.AddConfigPath(execDir)
+	// e.g. for the background cache refresh, to use the same config as the parent
+	if f := os.Getenv("HCTL_CONFIG"); f != "" {
+		v.SetConfigFile(f)
+	}
 
 	return v, nil
 }
@@ -96,12 +130,17 @@ func NewConfig() (*Config, error) {
 	cfg := &Config{}
 	cfg.Completion.ShortNames = true
 	cfg.Handling.Fuzz = true
+	cfg.Handling.CacheTTL = "5m"
 	cfg.Logging.LogLevel = "error"
 	cfg.Serve.IP = ""
 	cfg.Serve.Port = 1337
+	cfg.Retry.Retries = 2
+	cfg.Retry.Backoff = "500ms"
+	cfg.Retry.MaxBackoff = "10s"
 	cfg.Hub.Type = "hass"
 	cfg.Hub.URL = ""
 	cfg.Hub.Token = ""
+	cfg.Hub.Timeout = "30s"
 	cfg.DeviceMap = map[string]string{}
 	cfg.MediaMap = map[string]string{}
 
@@ -115,6 +154,7 @@ func NewConfig() (*Config, error) {
 	v.SetDefault("handling", &cfg.Handling)
 	v.SetDefault("logging", &cfg.Logging)
 	v.SetDefault("serve", &cfg.Serve)
+	v.SetDefault("retry", &cfg.Retry)
 	v.SetDefault("media_map", &cfg.MediaMap)
 	v.SetDefault("device_map", &cfg.DeviceMap)
 
@@ -138,6 +178,15 @@ func (c *Config) LoadConfig(configPath string) error {
 		return err
 	}
 
+	if err := validateProtectedEntities("protected_entities", c.ProtectedEntities); err != nil {
+		return err
+	}
+	for name, ctx := range c.Contexts {
+		if err := validateProtectedEntities(fmt.Sprintf("contexts.%s.protected_entities", name), ctx.ProtectedEntities); err != nil {
+			return err
+		}
+	}
+
 	logLevel := c.Viper.GetString("logging.log_level")
 	if logLevel != "" {
 		lvl, err := zerolog.ParseLevel(logLevel)
@@ -153,6 +202,31 @@ func (c *Config) LoadConfig(configPath string) error {
 	return nil
 }
 
+// GetCacheTTL returns handling.cache_ttl as duration, 0 disables the cache
+func (c *Config) GetCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(c.Handling.CacheTTL)
+	if err != nil {
+		log.Warn().Msgf("Invalid handling.cache_ttl `%s`, cache disabled: %v", c.Handling.CacheTTL, err)
+		return 0
+	}
+	return ttl
+}
+
+// GetRetryBackoff returns retry.backoff and retry.max_backoff as durations
+func (c *Config) GetRetryBackoff() (time.Duration, time.Duration) {
+	backoff, err := time.ParseDuration(c.Retry.Backoff)
+	if err != nil {
+		log.Warn().Msgf("Invalid retry.backoff `%s`, using 500ms: %v", c.Retry.Backoff, err)
+		backoff = 500 * time.Millisecond
+	}
+	maxBackoff, err := time.ParseDuration(c.Retry.MaxBackoff)
+	if err != nil {
+		log.Warn().Msgf("Invalid retry.max_backoff `%s`, using 10s: %v", c.Retry.MaxBackoff, err)
+		maxBackoff = 10 * time.Second
+	}
+	return backoff, maxBackoff
+}
+
 func (c *Config) GetServeIP() string {
 	return c.Serve.IP
 }
@@ -193,12 +267,19 @@ func getElementByYamlPath(p []string, v reflect.Value, t reflect.Type) (*reflect
 			case reflect.Struct:
 				return getElementByYamlPath(p[1:], field, typ.Type)
 			case reflect.Map:
+				if len(p) == 1 {
+					break
+				}
 				for _, e := range field.MapKeys() {
 					if e.String() != p[1] {
 						continue
 					}
 					val := field.MapIndex(e)
 					ty := val.Type()
+					// e.g. contexts.home.hub.url
+					if len(p) > 2 && val.Kind() == reflect.Struct {
+						return getElementByYamlPath(p[2:], val, ty)
+					}
 					return &val, &ty, nil
 				}
 			}
@@ -324,6 +405,9 @@ func (c *Config) RemoveOptionByPath(p string) error {
 		c.Viper.Set(s[0], m)
 		return nil
 	}
+	if s[0] == "contexts" && len(s) >= 2 {
+		return c.removeContextOption(s[1], s[2:])
+	}
 	return fmt.Errorf("deleting `%s` is currently not supported, use set instead", s[1])
 }
 
@@ -341,6 +425,9 @@ func (c *Config) SetValueByPath(p string, val any) error {
 		c.Viper.Set(s[0], m)
 		return nil
 	}
+	if s[0] == "contexts" {
+		return c.setContextValue(s[1], s[2:], val)
+	}
 	v, _, err := c.getElement(s)
 	if err != nil {
 		return err
@@ -348,6 +435,11 @@ func (c *Config) SetValueByPath(p string, val any) error {
 
 	log.Debug().Caller().Msgf("Config before change: %+v", c)
 
+	return setValue(v, val)
+}
+
+// setValue sets v to val, parsed according to the kind of v
+func setValue(v *reflect.Value, val any) error {
 	switch v.Kind() {
 	case reflect.String:
 		v.SetString(val.(string))
@@ -0,0 +1,186 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
+)
+
+// Context is a named hub with its own mappings, e.g. to switch between
+// a home, a lab and a parents' instance
+type Context struct {
+	Hub       Hub               `mapstructure:"hub" yaml:"hub" json:"hub"`
+	DeviceMap map[string]string `mapstructure:"device_map" yaml:"device_map" json:"device_map"`
+	MediaMap  map[string]string `mapstructure:"media_map" yaml:"media_map" json:"media_map"`
+	// ProtectedEntities are added to the top-level protected_entities
+	ProtectedEntities []string `mapstructure:"protected_entities" yaml:"protected_entities" json:"protected_entities"`
+}
+
+// GetContextNames returns the names of all configured contexts, sorted
+func (c *Config) GetContextNames() []string {
+	return slices.Sorted(maps.Keys(c.Contexts))
+}
+
+// BindContextFlag lets the given flag (--context) override HCTL_CONTEXT and current_context
+func (c *Config) BindContextFlag(flag *pflag.Flag) {
+	c.contextFlag = flag
+}
+
+// GetContext returns the name of the context in use, empty if none
+// --context takes precedence over HCTL_CONTEXT, which takes precedence over current_context
+func (c *Config) GetContext() string {
+	if c.contextFlag != nil && c.contextFlag.Changed {
+		return c.contextFlag.Value.String()
+	}
+	// not a config option, so it is not written to the config file, but AutomaticEnv
+	// still resolves it from HCTL_CONTEXT
+	if name := c.Viper.GetString("context"); name != "" {
+		return name
+	}
+	return c.CurrentContext
+}
+
+// CheckContext returns an error if the context in use does not exist
+func (c *Config) CheckContext() error {
+	name := c.GetContext()
+	if name == "" {
+		return nil
+	}
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("no such context: %s", name)
+	}
+	log.Info().Msgf("Using context %s", name)
+	return nil
+}
+
+// UseContext sets current_context and writes it to the config file
+func (c *Config) UseContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("no such context: %s", name)
+	}
+	c.CurrentContext = name
+	return c.WriteConfig()
+}
+
+// GetHub returns the hub of the context in use, or the top-level hub
+func (c *Config) GetHub() *Hub {
+	ctx, ok := c.Contexts[c.GetContext()]
+	if !ok {
+		return &c.Hub
+	}
+	hub := ctx.Hub
+	if hub.Type == "" {
+		hub.Type = "hass"
+	}
+	if hub.Timeout == "" {
+		hub.Timeout = c.Hub.Timeout
+	}
+	return &hub
+}
+
+// GetDeviceMap returns the device_map of the context in use, or the top-level one
+// The top-level mappings point to entities of the top-level hub, so they are not used in contexts
+func (c *Config) GetDeviceMap() map[string]string {
+	if ctx, ok := c.Contexts[c.GetContext()]; ok {
+		return maps.Clone(ctx.DeviceMap)
+	}
+	return c.Viper.GetStringMapString("device_map")
+}
+
+// GetMediaMap returns the media_map of the context in use, or the top-level one
+func (c *Config) GetMediaMap() map[string]string {
+	if ctx, ok := c.Contexts[c.GetContext()]; ok {
+		return maps.Clone(ctx.MediaMap)
+	}
+	return c.Viper.GetStringMapString("media_map")
+}
+
+// GetProtectedEntities returns the top-level protected_entities together with the ones of the context in use
+func (c *Config) GetProtectedEntities() []string {
+	protected := slices.Clone(c.ProtectedEntities)
+	for _, e := range c.Contexts[c.GetContext()].ProtectedEntities {
+		if !slices.Contains(protected, e) {
+			protected = append(protected, e)
+		}
+	}
+	return protected
+}
+
+// setContextValue sets e.g. contexts.lab.hub.url, creating the context if needed
+func (c *Config) setContextValue(name string, p []string, val any) error {
+	if c.Contexts == nil {
+		c.Contexts = map[string]Context{}
+	}
+	ctx := c.Contexts[name]
+
+	switch p[0] {
+	case "device_map", "media_map":
+		m := ctx.DeviceMap
+		if p[0] == "media_map" {
+			m = ctx.MediaMap
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[p[1]] = val.(string)
+		if p[0] == "media_map" {
+			ctx.MediaMap = m
+		} else {
+			ctx.DeviceMap = m
+		}
+	default:
+		// map values are not addressable, so we work on a copy
+		v, _, err := getElementByYamlPath(p, reflect.ValueOf(&ctx), reflect.TypeOf(&ctx))
+		if err != nil {
+			return err
+		}
+		if err := setValue(v, val); err != nil {
+			return err
+		}
+	}
+
+	c.Contexts[name] = ctx
+	return nil
+}
+
+// removeContextOption removes a whole context or a mapping of a context
+func (c *Config) removeContextOption(name string, p []string) error {
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		return fmt.Errorf("no such context: %s", name)
+	}
+	switch {
+	case len(p) == 0:
+		if name == c.CurrentContext {
+			c.CurrentContext = ""
+		}
+		delete(c.Contexts, name)
+		return nil
+	case len(p) == 2 && p[0] == "device_map":
+		delete(ctx.DeviceMap, p[1])
+	case len(p) == 2 && p[0] == "media_map":
+		delete(ctx.MediaMap, p[1])
+	default:
+		return fmt.Errorf("deleting `%s` is currently not supported, use set instead", p[len(p)-1])
+	}
+	c.Contexts[name] = ctx
+	return nil
+}
@@ -17,8 +17,12 @@ package config
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -38,6 +42,17 @@ func validateNonEmptyKey(p []string) error {
 	return nil
 }
 
+// validateProtectedEntities returns an error for entries of protected_entities that are no entity ids,
+// as e.g. a short name or a device_map key would silently protect nothing
+func validateProtectedEntities(key string, entities []string) error {
+	for _, e := range entities {
+		if d, n, ok := strings.Cut(e, "."); !ok || d == "" || n == "" {
+			return fmt.Errorf("%s needs full entity ids (e.g. lock.front_door): %s", key, e)
+		}
+	}
+	return nil
+}
+
 func validateLoggingString(value string) error {
 	_, err := zerolog.ParseLevel(value)
 	if err != nil {
@@ -80,16 +95,67 @@ func validateSetLogging(path []string, value any) error {
 	return fmt.Errorf("unknown config option for logging: %s", path[len(path)-1])
 }
 
+// validateFile allows empty values to unset a file option
+func validateFile(value string) error {
+	if value == "" {
+		return nil
+	}
+	if strings.HasPrefix(value, "~") {
+		return fmt.Errorf("file options do not support tilde path expansion yet")
+	}
+	if _, err := os.Stat(value); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateProxy allows empty values to use the proxy from the environment
+func validateProxy(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("Hub proxy needs to be a valid URL like http://proxy.example.com:3128")
+	}
+	if !slices.Contains([]string{"http", "https", "socks5"}, u.Scheme) {
+		return fmt.Errorf("unsupported proxy scheme: %s (Supported: http, https, socks5)", u.Scheme)
+	}
+	return nil
+}
+
+// hubTLSOptions are set as hub.tls.OPTION, all other hub options as hub.OPTION
+var hubTLSOptions = []string{"ca_file", "cert_file", "key_file", "insecure_skip_verify"}
+
 func validateSetHub(path []string, value any) error {
 	log.Debug().Caller().Msgf("Validating set for %s: %+v", path, value)
 	opt := path[len(path)-1]
+	if slices.Contains(hubTLSOptions, opt) {
+		if len(path) != 3 || path[1] != "tls" {
+			return fmt.Errorf("unknown config option for hub: %s", opt)
+		}
+	} else if len(path) != 2 {
+		return fmt.Errorf("unknown config option for hub: %s", strings.Join(path[1:], "."))
+	}
 	switch opt {
 	case "type":
-		if value != "hass" {
-			return fmt.Errorf("unknown hub type: %s (Supported: hass)", value)
+		if !slices.Contains(HubTypes, value.(string)) {
+			return fmt.Errorf("unknown hub type: %s (Supported: %s)", value, strings.Join(HubTypes, ", "))
 		}
 	case "url":
 	case "token":
+	case "ca_file", "cert_file", "key_file":
+		return validateFile(value.(string))
+	case "insecure_skip_verify":
+		if _, err := strconv.ParseBool(value.(string)); err != nil {
+			return fmt.Errorf("Hub tls insecure_skip_verify needs to be true/false")
+		}
+	case "timeout":
+		if d, err := time.ParseDuration(value.(string)); err != nil || d < 0 {
+			return fmt.Errorf("Hub timeout needs to be a duration like 10s or 1m (0 disables the timeout)")
+		}
+	case "proxy":
+		return validateProxy(value.(string))
 	default:
 		return fmt.Errorf("unknown config option for hub: %s", opt)
 	}
@@ -105,6 +171,11 @@ func validateSetHandling(path []string, value any) error {
 		if _, err := strconv.ParseBool(s); err != nil {
 			return fmt.Errorf("Handling fuzz needs to be true/false")
 		}
+	case "cache_ttl":
+		s := value.(string)
+		if d, err := time.ParseDuration(s); err != nil || d < 0 {
+			return fmt.Errorf("Handling cache_ttl needs to be a duration like 30s, 5m or 1h (0 disables the cache)")
+		}
 	default:
 		return fmt.Errorf("unknown config option for handling: %s", opt)
 	}
@@ -156,6 +227,43 @@ func validateSetServe(path []string, value any) error {
 	return nil
 }
 
+func validateSetRetry(path []string, value any) error {
+	log.Debug().Caller().Msgf("Validating set for %s: %+v", path, value)
+	opt := path[len(path)-1]
+	switch opt {
+	case "retries":
+		s := value.(string)
+		if n, err := strconv.Atoi(s); err != nil || n < 0 {
+			return fmt.Errorf("Retry retries needs to be a number >= 0")
+		}
+	case "backoff", "max_backoff":
+		s := value.(string)
+		if d, err := time.ParseDuration(s); err != nil || d < 0 {
+			return fmt.Errorf("Retry %s needs to be a duration like 500ms or 10s", opt)
+		}
+	default:
+		return fmt.Errorf("unknown config option for retry: %s", opt)
+	}
+	return nil
+}
+
+func validateSetContext(path []string, value any) error {
+	log.Debug().Caller().Msgf("Validating set for %s: %+v", path, value)
+	if len(path) < 4 {
+		return fmt.Errorf("cannot set value for section: %s", strings.Join(path, "."))
+	}
+	switch path[2] {
+	case "hub":
+		return validateSetHub(path[2:], value)
+	case "device_map":
+		return validateSetDeviceMap(path[2:], value)
+	case "media_map":
+		return validateSetMediaMap(path[2:], value)
+	default:
+		return fmt.Errorf("unknown config option for context: %s", path[2])
+	}
+}
+
 func validateSet(path string, value any) error {
 	p := strings.Split(path, ".")
 	if err := validateIsSection(p); err != nil {
@@ -180,6 +288,10 @@ func validateSet(path string, value any) error {
 		return validateSetCompletion(p, value)
 	case "serve":
 		return validateSetServe(p, value)
+	case "retry":
+		return validateSetRetry(p, value)
+	case "contexts":
+		return validateSetContext(p, value)
 	default:
 		return fmt.Errorf("unknown config option: %s", path)
 	}
@@ -0,0 +1,70 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// CoverAction opens, closes or stops a cover and, if wait is set, waits until it is open or closed
+// Returns the entity id
+func (h *Hctl) CoverAction(obj string, action string, wait time.Duration) (string, string, []rest.HassResult, error) {
+	c, err := h.GetHass("cover")
+	if err != nil {
+		return "", "", nil, err
+	}
+	obj, state, sub, results, err := c.CoverAction(obj, action)
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		return "", "", nil, err
+	}
+	log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
+	if wait > 0 {
+		r, err := c.Wait(wait)
+		if err != nil {
+			return "", "", nil, err
+		}
+		results = rest.MergeResult(results, r)
+	}
+	return fmt.Sprintf("%s.%s", sub, obj), state, results, nil
+}
+
+// CoverPosition sets the position (or tilt position) of a cover and, if wait is set, waits until it has been reached
+// Returns the entity id
+func (h *Hctl) CoverPosition(obj string, position string, tilt bool, wait time.Duration) (string, string, []rest.HassResult, error) {
+	c, err := h.GetHass("cover")
+	if err != nil {
+		return "", "", nil, err
+	}
+	obj, state, sub, results, err := c.CoverPosition(obj, position, tilt)
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		return "", "", nil, err
+	}
+	log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
+	if wait > 0 {
+		r, err := c.Wait(wait)
+		if err != nil {
+			return "", "", nil, err
+		}
+		results = rest.MergeResult(results, r)
+	}
+	return fmt.Sprintf("%s.%s", sub, obj), state, results, nil
+}
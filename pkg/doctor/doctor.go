@@ -0,0 +1,351 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xx4h/hctl/pkg/client"
+	"github.com/xx4h/hctl/pkg/config"
+	"github.com/xx4h/hctl/pkg/util"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+	// Skip is used for checks that depend on a failed one
+	Skip Status = "skip"
+)
+
+const (
+	tokenExpiryWarning = 30 * 24 * time.Hour
+	clockSkewWarning   = 30 * time.Second
+	latencyWarning     = time.Second
+	serveTimeout       = 2 * time.Second
+)
+
+// Check is a single line of the report
+type Check struct {
+	Name    string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Doctor checks the whole path from the config file over the hub to the local media server
+type Doctor struct {
+	ConfigFile string
+	Context    string
+	Hub        *config.Hub
+	// Client is created from the tls, timeout and proxy settings of Hub if nil
+	Client    *http.Client
+	ServeIP   string
+	ServePort int
+}
+
+// Failed returns true if any of the checks failed
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+func pass(name string, format string, a ...any) Check {
+	return Check{Name: name, Status: Pass, Message: fmt.Sprintf(format, a...)}
+}
+
+func warn(name string, format string, a ...any) Check {
+	return Check{Name: name, Status: Warn, Message: fmt.Sprintf(format, a...)}
+}
+
+func fail(name string, format string, a ...any) Check {
+	return Check{Name: name, Status: Fail, Message: fmt.Sprintf(format, a...)}
+}
+
+func skip(name string, reason string) Check {
+	return Check{Name: name, Status: Skip, Message: reason}
+}
+
+// Run runs all checks in order, checks that depend on a failed one are skipped
+func (d *Doctor) Run() []Check {
+	checks := []Check{d.checkConfig()}
+
+	urlCheck := d.checkURL()
+	checks = append(checks, urlCheck, d.checkToken())
+	if urlCheck.Status == Fail {
+		for _, name := range []string{"reachability", "api", "latency", "clock", "version"} {
+			checks = append(checks, skip(name, "no valid hub url"))
+		}
+		return append(checks, d.checkServe())
+	}
+
+	start := time.Now()
+	body, res, err := d.get("/")
+	latency := time.Since(start)
+	if err != nil {
+		checks = append(checks, fail("reachability", "%s: %v", d.Hub.URL, err))
+		for _, name := range []string{"api", "latency", "clock", "version"} {
+			checks = append(checks, skip(name, "hub not reachable"))
+		}
+		return append(checks, d.checkServe())
+	}
+	apiCheck := d.checkAPI(res.StatusCode, body)
+	checks = append(checks,
+		pass("reachability", "%s answered with %s", d.Hub.URL, res.Status),
+		apiCheck,
+		checkLatency(latency),
+		checkClock(res.Header.Get("Date"), start.Add(latency/2)),
+	)
+	if apiCheck.Status == Fail {
+		checks = append(checks, skip("version", "api check failed"))
+	} else {
+		checks = append(checks, d.checkVersion(body))
+	}
+	return append(checks, d.checkServe())
+}
+
+func (d *Doctor) get(path string) ([]byte, *http.Response, error) {
+	req, err := http.NewRequest("GET", d.Hub.URL+path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.Hub.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.Hub.Token))
+	}
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, res, nil
+}
+
+func (d *Doctor) checkConfig() Check {
+	name := "config"
+	if d.ConfigFile == "" {
+		return warn(name, "no config file found, using defaults and environment only (run `hctl init`)")
+	}
+	if _, err := os.Stat(d.ConfigFile); err != nil {
+		return fail(name, "%v", err)
+	}
+	if d.Context != "" {
+		return pass(name, "%s (context %s)", d.ConfigFile, d.Context)
+	}
+	return pass(name, "%s", d.ConfigFile)
+}
+
+func (d *Doctor) checkURL() Check {
+	name := "hub url"
+	if d.Hub.URL == "" {
+		return fail(name, "no hub url configured (hub.url)")
+	}
+	u, err := url.Parse(d.Hub.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fail(name, "not a valid url: %s", d.Hub.URL)
+	}
+	if d.Client == nil {
+		// e.g. invalid tls or proxy settings
+		c, err := client.New(d.Hub)
+		if err != nil {
+			return fail(name, "invalid connection settings: %v", err)
+		}
+		d.Client = c
+	}
+	if u.Scheme != "https" {
+		return warn(name, "%s (%s hub, token is sent unencrypted)", d.Hub.URL, d.Hub.Type)
+	}
+	return pass(name, "%s (%s hub)", d.Hub.URL, d.Hub.Type)
+}
+
+func (d *Doctor) checkToken() Check {
+	name := "token"
+	if d.Hub.Type == "openhab" {
+		// openHAB API tokens are no JWT, and may be omitted for anonymous access
+		if d.Hub.Token == "" {
+			return warn(name, "no token configured, anonymous access only")
+		}
+		return pass(name, "configured")
+	}
+	if d.Hub.Token == "" {
+		return fail(name, "no token configured (hub.token)")
+	}
+	t, _, err := new(jwt.Parser).ParseUnverified(d.Hub.Token, jwt.MapClaims{})
+	if err != nil {
+		return fail(name, "not a valid token (JWT): %v", err)
+	}
+	exp, err := t.Claims.GetExpirationTime()
+	if err != nil {
+		return fail(name, "invalid expiry: %v", err)
+	}
+	if exp == nil {
+		return pass(name, "valid, does not expire")
+	}
+	left := time.Until(exp.Time)
+	switch {
+	case left <= 0:
+		return fail(name, "expired at %s", exp.Local().Format(time.DateTime))
+	case left < tokenExpiryWarning:
+		return warn(name, "expires in %d days (%s)", int(left.Hours()/24), exp.Local().Format(time.DateTime))
+	}
+	return pass(name, "valid until %s", exp.Local().Format(time.DateOnly))
+}
+
+func (d *Doctor) checkAPI(status int, body []byte) Check {
+	name := "api"
+	switch {
+	case status == http.StatusUnauthorized:
+		return fail(name, "token rejected by the hub (%d)", status)
+	case status != http.StatusOK:
+		return fail(name, "unexpected response code %d", status)
+	}
+	var a map[string]any
+	if err := json.Unmarshal(body, &a); err != nil {
+		return fail(name, "no JSON response, is the url pointing to the API (e.g. https://hass.example.com/api)?")
+	}
+	// Home Assistant answers with a message, openHAB with the version of its REST API
+	key := "message"
+	if d.Hub.Type == "openhab" {
+		key = "version"
+	}
+	v, ok := a[key]
+	if !ok {
+		return fail(name, "unexpected response: %s", string(body))
+	}
+	if d.Hub.Type == "openhab" {
+		return pass(name, "REST API version %v", v)
+	}
+	return pass(name, "%v", v)
+}
+
+func checkLatency(latency time.Duration) Check {
+	name := "latency"
+	if latency > latencyWarning {
+		return warn(name, "%s round trip, commands will feel slow", latency.Round(time.Millisecond))
+	}
+	return pass(name, "%s round trip", latency.Round(time.Millisecond))
+}
+
+// checkClock compares the Date header of the hub with the local time the response was sent at
+func checkClock(date string, local time.Time) Check {
+	name := "clock"
+	if date == "" {
+		return skip(name, "hub sent no Date header")
+	}
+	remote, err := http.ParseTime(date)
+	if err != nil {
+		return skip(name, fmt.Sprintf("invalid Date header: %s", date))
+	}
+	// the Date header has a resolution of one second
+	skew := local.Sub(remote).Truncate(time.Second)
+	if skew.Abs() > clockSkewWarning {
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+		}
+		return warn(name, "local clock is %s %s the hub, check NTP (token expiry and history periods depend on it)", skew.Abs(), direction)
+	}
+	return pass(name, "in sync with the hub (skew %s)", skew)
+}
+
+func (d *Doctor) checkVersion(root []byte) Check {
+	name := "version"
+	if d.Hub.Type == "openhab" {
+		var info struct {
+			RuntimeInfo struct {
+				Version string `json:"version"`
+			} `json:"runtimeInfo"`
+		}
+		if err := json.Unmarshal(root, &info); err != nil || info.RuntimeInfo.Version == "" {
+			return warn(name, "hub sent no runtime info")
+		}
+		return pass(name, "openHAB %s", info.RuntimeInfo.Version)
+	}
+
+	body, res, err := d.get("/config")
+	if err != nil {
+		return fail(name, "%v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fail(name, "unexpected response code %d for /config", res.StatusCode)
+	}
+	var cfg struct {
+		Version      string            `json:"version"`
+		LocationName string            `json:"location_name"`
+		UnitSystem   map[string]string `json:"unit_system"`
+	}
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return fail(name, "invalid /config response: %v", err)
+	}
+	units := "metric"
+	if cfg.UnitSystem["temperature"] == "°F" {
+		units = "US customary"
+	}
+	return pass(name, "Home Assistant %s at %s, %s units (%s, %s)", cfg.Version, cfg.LocationName, units, cfg.UnitSystem["temperature"], cfg.UnitSystem["length"])
+}
+
+// checkServe binds serve.ip and serve.port like `hctl play` does for local files, and requests it
+func (d *Doctor) checkServe() Check {
+	name := "serve"
+	ip := d.ServeIP
+	if ip == "" {
+		var err error
+		if ip, err = util.LocalIP(); err != nil {
+			return fail(name, "could not determine local IP, set serve.ip: %v", err)
+		}
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(d.ServePort)))
+	if err != nil {
+		return fail(name, "cannot bind: %v", err)
+	}
+	srv := &http.Server{
+		ReadHeaderTimeout: serveTimeout,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, "ready")
+		}),
+	}
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Close()
+
+	addr := fmt.Sprintf("http://%s", ln.Addr().String())
+	c := &http.Client{Timeout: serveTimeout}
+	res, err := c.Get(addr + "/ready")
+	if err != nil {
+		return warn(name, "bound %s, but could not reach it: %v", addr, err)
+	}
+	res.Body.Close()
+	return pass(name, "%s can be bound and reached, the hub needs to reach it as well to play local files", addr)
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xx4h/hctl/pkg/config"
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func token(t *testing.T, exp time.Duration) string {
+	t.Helper()
+	claims := jwt.MapClaims{"iss": "hctl-test"}
+	if exp != 0 {
+		claims["exp"] = time.Now().Add(exp).Unix()
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func statuses(checks []Check) map[string]Status {
+	m := map[string]Status{}
+	for _, c := range checks {
+		m[c.Name] = c.Status
+	}
+	return m
+}
+
+func Test_Run(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	oh := hctltest.OpenHABMockServer(t)
+	defer oh.Close()
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+	skewed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"message": "API running."}`))
+	}))
+	defer skewed.Close()
+
+	configFile := path.Join(t.TempDir(), "hctl.yaml")
+	if err := os.WriteFile(configFile, []byte("hub:\n  type: hass\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		doctor Doctor
+		want   map[string]Status
+		failed bool
+	}{
+		"healthy": {
+			doctor: Doctor{ConfigFile: configFile, Hub: &config.Hub{Type: "hass", URL: ms.URL, Token: token(t, 0)}},
+			want: map[string]Status{
+				"config": Pass, "hub url": Warn, "token": Pass, "reachability": Pass,
+				"api": Pass, "latency": Pass, "clock": Pass, "version": Pass, "serve": Pass,
+			},
+		},
+		"no config file and token expiring soon": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: ms.URL, Token: token(t, 48*time.Hour)}},
+			want:   map[string]Status{"config": Warn, "token": Warn, "version": Pass},
+		},
+		"missing config file and expired token": {
+			doctor: Doctor{ConfigFile: configFile + ".missing", Hub: &config.Hub{Type: "hass", URL: ms.URL, Token: token(t, -time.Hour)}},
+			want:   map[string]Status{"config": Fail, "token": Fail},
+			failed: true,
+		},
+		"no jwt": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: ms.URL, Token: "test"}},
+			want:   map[string]Status{"token": Fail, "api": Pass},
+			failed: true,
+		},
+		"invalid url": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: "hass.local", Token: token(t, 0)}},
+			want:   map[string]Status{"hub url": Fail, "reachability": Skip, "version": Skip, "serve": Pass},
+			failed: true,
+		},
+		"unreachable": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: "http://127.0.0.1:1/api", Token: token(t, 0)}},
+			want:   map[string]Status{"reachability": Fail, "api": Skip, "clock": Skip},
+			failed: true,
+		},
+		"token rejected": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: unauthorized.URL, Token: token(t, 0)}},
+			want:   map[string]Status{"reachability": Pass, "api": Fail, "version": Skip},
+			failed: true,
+		},
+		"clock skew": {
+			doctor: Doctor{Hub: &config.Hub{Type: "hass", URL: skewed.URL, Token: token(t, 0)}},
+			want:   map[string]Status{"clock": Warn},
+		},
+		"openhab without token": {
+			doctor: Doctor{Hub: &config.Hub{Type: "openhab", URL: oh.URL}},
+			want:   map[string]Status{"token": Warn, "api": Pass, "version": Pass},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.doctor.ServeIP = "127.0.0.1"
+			checks := tt.doctor.Run()
+			got := statuses(checks)
+			for check, want := range tt.want {
+				if got[check] != want {
+					t.Errorf("%s: got %s, want %s (%v)", check, got[check], want, checks)
+				}
+			}
+			if Failed(checks) != tt.failed {
+				t.Errorf("got failed %v, want %v", Failed(checks), tt.failed)
+			}
+		})
+	}
+}
+
+func Test_RunMessages(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	d := Doctor{Hub: &config.Hub{Type: "hass", URL: ms.URL, Token: token(t, 0)}, ServeIP: "127.0.0.1"}
+	for _, c := range d.Run() {
+		if c.Name == "version" && c.Message != "Home Assistant 2024.10.1 at Home, metric units (°C, km)" {
+			t.Errorf("got version %q", c.Message)
+		}
+		if c.Name == "api" && c.Message != "API running." {
+			t.Errorf("got api %q", c.Message)
+		}
+	}
+}
+
+func Test_checkServePortInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	d := Doctor{ServeIP: "127.0.0.1", ServePort: ln.Addr().(*net.TCPAddr).Port}
+	c := d.checkServe()
+	if c.Status != Fail || !strings.Contains(c.Message, "cannot bind") {
+		t.Errorf("got %+v, want cannot bind", c)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// fan runs set for a fan and, if wait is set, waits until its target has been reached
+// Returns the entity id
+func (h *Hctl) fan(wait time.Duration, set func(c *rest.Hass) (string, string, string, []rest.HassResult, error)) (string, string, []rest.HassResult, error) {
+	c, err := h.GetHass("fan")
+	if err != nil {
+		return "", "", nil, err
+	}
+	obj, state, sub, results, err := set(c)
+	if err != nil {
+		log.Debug().Caller().Msgf("Error: %+v", err)
+		return "", "", nil, err
+	}
+	log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
+	if wait > 0 {
+		r, err := c.Wait(wait)
+		if err != nil {
+			return "", "", nil, err
+		}
+		results = rest.MergeResult(results, r)
+	}
+	return fmt.Sprintf("%s.%s", sub, obj), state, results, nil
+}
+
+// FanPercentage sets the speed of a fan (0-100, or +/- to step by its `percentage_step`)
+func (h *Hctl) FanPercentage(obj string, percentage string, wait time.Duration) (string, string, []rest.HassResult, error) {
+	return h.fan(wait, func(c *rest.Hass) (string, string, string, []rest.HassResult, error) {
+		return c.FanPercentage(obj, percentage)
+	})
+}
+
+// FanPresetMode sets the preset mode of a fan
+func (h *Hctl) FanPresetMode(obj string, mode string, wait time.Duration) (string, string, []rest.HassResult, error) {
+	return h.fan(wait, func(c *rest.Hass) (string, string, string, []rest.HassResult, error) {
+		return c.FanPresetMode(obj, mode)
+	})
+}
+
+// FanOscillate turns the oscillation of a fan on or off
+func (h *Hctl) FanOscillate(obj string, oscillating bool) (string, string, []rest.HassResult, error) {
+	return h.fan(0, func(c *rest.Hass) (string, string, string, []rest.HassResult, error) {
+		return c.FanOscillate(obj, oscillating)
+	})
+}
+
+// FanDirection sets the direction of a fan to forward or reverse
+func (h *Hctl) FanDirection(obj string, direction string) (string, string, []rest.HassResult, error) {
+	return h.fan(0, func(c *rest.Hass) (string, string, string, []rest.HassResult, error) {
+		return c.FanDirection(obj, direction)
+	})
+}
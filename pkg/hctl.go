@@ -17,23 +17,33 @@ package pkg
 import (
 	"fmt"
 	"io"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
 
+	"github.com/xx4h/hctl/pkg/backend"
+	"github.com/xx4h/hctl/pkg/client"
 	"github.com/xx4h/hctl/pkg/config"
+	"github.com/xx4h/hctl/pkg/doctor"
 	i "github.com/xx4h/hctl/pkg/init"
 	o "github.com/xx4h/hctl/pkg/output"
 	"github.com/xx4h/hctl/pkg/rest"
 	"github.com/xx4h/hctl/pkg/serve"
 	"github.com/xx4h/hctl/pkg/util"
+	"github.com/xx4h/hctl/pkg/ws"
 )
 
 type Hctl struct {
-	cfg *config.Config
+	cfg     *config.Config
+	client  *http.Client
+	confirm rest.ConfirmFunc
 	// out io.ReadWriteCloser
 	// log *zerolog.Logger
 }
@@ -91,9 +101,45 @@ func (h *Hctl) SetConfigValue(p string, v string) error {
 }
 
 func (h *Hctl) GetMap(k string) map[string]string {
+	switch k {
+	case "device_map":
+		return h.cfg.GetDeviceMap()
+	case "media_map":
+		return h.cfg.GetMediaMap()
+	}
 	return h.cfg.Viper.GetStringMapString(k)
 }
 
+// GetContext returns the name of the context in use, empty if none
+func (h *Hctl) GetContext() string {
+	return h.cfg.GetContext()
+}
+
+// GetContexts returns the names of all configured contexts
+func (h *Hctl) GetContexts() []string {
+	return h.cfg.GetContextNames()
+}
+
+// GetContextHub returns the hub of the given context
+func (h *Hctl) GetContextHub(name string) config.Hub {
+	return h.cfg.Contexts[name].Hub
+}
+
+// BindContextFlag lets the given flag (--context) select the context
+func (h *Hctl) BindContextFlag(flag *pflag.Flag) {
+	h.cfg.BindContextFlag(flag)
+}
+
+// CheckContext returns an error if the context in use does not exist
+func (h *Hctl) CheckContext() error {
+	return h.cfg.CheckContext()
+}
+
+// UseContext sets current_context in the config file
+func (h *Hctl) UseContext(name string) error {
+	return h.cfg.UseContext(name)
+}
+
 func (h *Hctl) RemoveConfigOption(p string) error {
 	err := h.cfg.RemoveOptionByPath(p)
 	return err
@@ -113,12 +159,76 @@ func (h *Hctl) GetConfigOptionsAsPaths() []string {
 	return h.cfg.GetOptionsAsPaths()
 }
 
+// HTTPClient returns the http client shared by all requests to the hub
+func (h *Hctl) HTTPClient() *http.Client {
+	if h.client == nil {
+		c, err := client.New(h.cfg.GetHub())
+		if err != nil {
+			log.Fatal().Msgf("Error: %+v", err)
+		}
+		h.client = c
+	}
+	return h.client
+}
+
+// GetBackend returns the client for hub.type of the hub in use
+func (h *Hctl) GetBackend() backend.Backend {
+	hub := h.cfg.GetHub()
+	backoff, maxBackoff := h.cfg.GetRetryBackoff()
+	b, err := backend.New(hub, backend.Options{
+		Fuzz:      h.cfg.Handling.Fuzz,
+		DeviceMap: h.cfg.GetDeviceMap(),
+		Protected: h.cfg.GetProtectedEntities(),
+		Confirm:   h.confirm,
+		Client:    h.HTTPClient(),
+		Retry:     rest.Retry{Retries: h.cfg.Retry.Retries, Backoff: backoff, MaxBackoff: maxBackoff},
+	})
+	if err != nil {
+		log.Fatal().Msgf("Error: %+v", err)
+	}
+	return b
+}
+
+// requireHass returns an error if the hub in use is not a Home Assistant hub
+func (h *Hctl) requireHass(feature string) error {
+	if t := h.cfg.GetHub().Type; t != "hass" {
+		return fmt.Errorf("%s is only supported for Home Assistant hubs (hub type: %s)", feature, t)
+	}
+	return nil
+}
+
+// GetHass returns the Home Assistant client, for features only Home Assistant offers
+func (h *Hctl) GetHass(feature string) (*rest.Hass, error) {
+	if err := h.requireHass(feature); err != nil {
+		return nil, err
+	}
+	return h.GetRest(), nil
+}
+
 func (h *Hctl) GetRest() *rest.Hass {
-	return rest.New(h.cfg.Hub.URL, h.cfg.Hub.Token, h.cfg.Handling.Fuzz, h.cfg.DeviceMap)
+	hub := h.cfg.GetHub()
+	r := rest.New(hub.URL, hub.Token, h.cfg.Handling.Fuzz, h.cfg.GetDeviceMap())
+	r.Protected = h.cfg.GetProtectedEntities()
+	r.Confirm = h.confirm
+	r.Client = h.HTTPClient()
+	backoff, maxBackoff := h.cfg.GetRetryBackoff()
+	r.Retry = rest.Retry{Retries: h.cfg.Retry.Retries, Backoff: backoff, MaxBackoff: maxBackoff}
+	return r
+}
+
+func (h *Hctl) GetWebSocket() *ws.Client {
+	hub := h.cfg.GetHub()
+	c := ws.New(hub.URL, hub.Token)
+	d, err := client.NewDialer(hub)
+	if err != nil {
+		log.Fatal().Msgf("Error: %+v", err)
+	}
+	c.Dialer = d
+	return c
 }
 
 func (h *Hctl) GetServices() ([]rest.HassService, error) {
-	services, err := h.GetRest().GetServices()
+	services, err := h.GetBackend().GetServices()
 	if err != nil {
 		log.Fatal().Msgf("Error: %+v", err)
 		return nil, err
@@ -127,15 +237,73 @@ func (h *Hctl) GetServices() ([]rest.HassService, error) {
 }
 
 func (h *Hctl) GetStates() ([]rest.HassState, error) {
-	states, err := h.GetRest().GetStates()
+	states, err := h.GetBackend().GetStates()
 	if err != nil {
 		return nil, err
 	}
 	return states, nil
 }
 
+// GetEntity resolves name (short, mapped or fuzzy) and returns the current state of the entity
+func (h *Hctl) GetEntity(name string) (rest.HassState, error) {
+	c := h.GetBackend()
+	domain, obj, err := c.ResolveEntity(name)
+	if err != nil {
+		return rest.HassState{}, err
+	}
+	return c.FetchState(domain, obj)
+}
+
+// RenderTemplate renders a Jinja template on the hub
+func (h *Hctl) RenderTemplate(template string) (string, error) {
+	c, err := h.GetHass("template")
+	if err != nil {
+		return "", err
+	}
+	return c.RenderTemplate(template)
+}
+
+// Doctor checks config, hub and local media server and returns a line per check
+func (h *Hctl) Doctor() []doctor.Check {
+	d := &doctor.Doctor{
+		ConfigFile: h.cfg.Viper.ConfigFileUsed(),
+		Context:    h.cfg.GetContext(),
+		Hub:        h.cfg.GetHub(),
+		ServeIP:    h.cfg.GetServeIP(),
+		ServePort:  h.cfg.GetServePort(),
+	}
+	return d.Run()
+}
+
+// Snapshot returns the entity id and the current image of a camera or image entity
+func (h *Hctl) Snapshot(name string) (string, []byte, error) {
+	c, err := h.GetHass("snapshot")
+	if err != nil {
+		return "", nil, err
+	}
+	return c.GetImage(name)
+}
+
+// GetEvents returns all event types with listeners
+func (h *Hctl) GetEvents() ([]rest.HassEvent, error) {
+	c, err := h.GetHass("events")
+	if err != nil {
+		return nil, err
+	}
+	return c.GetEvents()
+}
+
+// FireEvent fires a custom event on the hub
+func (h *Hctl) FireEvent(eventType string, data map[string]any) (string, error) {
+	c, err := h.GetHass("events")
+	if err != nil {
+		return "", err
+	}
+	return c.FireEvent(eventType, data)
+}
+
 func (h *Hctl) GetFilteredServices(domains []string, services []string) []rest.HassService {
-	s, err := h.GetRest().GetFilteredServices(domains, services)
+	s, err := h.GetBackend().GetFilteredServices(domains, services)
 	if err != nil {
 		log.Fatal().Msgf("Error: %+v", err)
 		return nil
@@ -144,11 +312,11 @@ func (h *Hctl) GetFilteredServices(domains []string, services []string) []rest.H
 }
 
 func (h *Hctl) GetFilteredStates(domains []string) ([]rest.HassState, error) {
-	return h.GetRest().GetFilteredStates(domains)
+	return h.GetBackend().GetFilteredStates(domains)
 }
 
 func (h *Hctl) GetFilteredServicesMap(domains []string, services []string) map[string][]string {
-	t, err := h.GetRest().GetFilteredServicesMap(domains, services)
+	t, err := h.GetBackend().GetFilteredServicesMap(domains, services)
 	if err != nil {
 		log.Fatal().Msgf("Error: %+v", err)
 		return nil
@@ -157,10 +325,22 @@ func (h *Hctl) GetFilteredServicesMap(domains []string, services []string) map[s
 }
 
 func (h *Hctl) GetFilteredStatesMap(domains []string) (map[string][]string, error) {
-	return h.GetRest().GetFilteredStatesMap(domains)
+	return h.GetBackend().GetFilteredStatesMap(domains)
 }
 
 func (h *Hctl) DumpServices(out io.Writer, domains []string, services []string) {
+	if o.Structured() {
+		for _, d := range h.GetFilteredServices(domains, services) {
+			for _, name := range slices.Sorted(maps.Keys(d.Services)) {
+				svc := d.Services[name]
+				r := o.Row{Keys: []string{"domain", "service", "name", "description"}, Values: []any{d.Domain, name, svc.Name, svc.Description}}
+				if err := o.FprintRecord(out, r); err != nil {
+					o.FprintError(out, err)
+				}
+			}
+		}
+		return
+	}
 	t := h.GetFilteredServicesMap(domains, services)
 	if err := o.PrintThreeLevelFlatTree(out, "Services", t); err != nil {
 		log.Error().Msgf("Error: %+v", err)
@@ -168,6 +348,18 @@ func (h *Hctl) DumpServices(out io.Writer, domains []string, services []string)
 }
 
 func (h *Hctl) DumpStates(out io.Writer, domains []string) {
+	if o.Structured() {
+		states, err := h.GetFilteredStates(domains)
+		if err != nil {
+			o.FprintError(out, err)
+		}
+		for _, s := range states {
+			if err := o.FprintRecord(out, s); err != nil {
+				o.FprintError(out, err)
+			}
+		}
+		return
+	}
 	t, err := h.GetFilteredStatesMap(domains)
 	if err != nil {
 		o.FprintError(out, err)
@@ -178,7 +370,7 @@ func (h *Hctl) DumpStates(out io.Writer, domains []string) {
 }
 
 func (h *Hctl) PlayMusic(out io.Writer, target string, mediaURL string) {
-	if mapURL, ok := h.cfg.MediaMap[mediaURL]; ok {
+	if mapURL, ok := h.cfg.GetMediaMap()[mediaURL]; ok {
 		mediaURL = mapURL
 	}
 
@@ -186,11 +378,11 @@ func (h *Hctl) PlayMusic(out io.Writer, target string, mediaURL string) {
 	if ok := util.IsURL(mediaURL); ok {
 		// if we already have a url, just play it
 
-		if obj, state, sub, err := h.GetRest().PlayMusic(target, mediaURL, mediaURL); err != nil {
+		if obj, state, sub, results, err := h.GetBackend().PlayMusic(target, mediaURL, mediaURL); err != nil {
 			log.Debug().Caller().Msgf("Error: %+v", err)
 			o.FprintError(out, err)
 		} else {
-			o.PrintSuccessAction(obj, state)
+			o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), state, results)
 			log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 		}
 
@@ -212,13 +404,13 @@ func (h *Hctl) PlayMusic(out io.Writer, target string, mediaURL string) {
 		}
 
 		// we are ready and send the url to play
-		obj, state, sub, err := h.GetRest().PlayMusic(target, s.GetURL(), s.GetMediaName())
+		obj, state, sub, results, err := h.GetBackend().PlayMusic(target, s.GetURL(), s.GetMediaName())
 		if err != nil {
 			log.Debug().Caller().Msgf("Error: %+v", err)
 			o.FprintError(out, err)
 		}
 
-		o.FprintSuccessAction(out, obj, state)
+		o.FprintResult(out, fmt.Sprintf("%s.%s", sub, obj), state, results)
 		log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
 		// TODO: find better way to ensure we don't close the server before file has been served
 		// -> RaceCondition
@@ -231,34 +423,59 @@ func (h *Hctl) PlayMusic(out io.Writer, target string, mediaURL string) {
 	}
 }
 
-func (h *Hctl) VolumeSet(obj string, volume string) (string, string, error) {
+// VolumeSet sets the volume and, if wait is set, waits until the volume has been applied
+// Returns the entity id
+func (h *Hctl) VolumeSet(obj string, volume string, wait time.Duration) (string, string, []rest.HassResult, error) {
 	vint, err := strconv.Atoi(volume)
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
-		return "", "", err
+		return "", "", nil, err
 	}
-	obj, state, sub, err := h.GetRest().VolumeSet(obj, vint)
+	c := h.GetBackend()
+	obj, state, sub, results, err := c.VolumeSet(obj, vint)
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
-		return "", "", err
+		return "", "", nil, err
 	}
 	log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
-	return obj, state, nil
+	if wait > 0 {
+		r, err := c.Wait(wait)
+		if err != nil {
+			return "", "", nil, err
+		}
+		results = rest.MergeResult(results, r)
+	}
+	return fmt.Sprintf("%s.%s", sub, obj), state, results, nil
 }
 
-func (h *Hctl) TemperatureSet(obj string, temp string) (string, string, error) {
+// TemperatureSet sets the temperature and, if wait is set, waits until the temperature has been applied
+// Returns the entity id
+func (h *Hctl) TemperatureSet(obj string, temp string, wait time.Duration) (string, string, []rest.HassResult, error) {
 	tint, err := strconv.ParseFloat(temp, 64)
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
-		return "", "", err
+		return "", "", nil, err
 	}
-	obj, state, sub, err := h.GetRest().TemperatureSet(obj, tint)
+	c := h.GetBackend()
+	obj, state, sub, results, err := c.TemperatureSet(obj, tint)
 	if err != nil {
 		log.Debug().Caller().Msgf("Error: %+v", err)
-		return "", "", err
+		return "", "", nil, err
 	}
 	log.Debug().Caller().Msgf("Result: %s(%s) to %s", obj, sub, state)
-	return obj, state, nil
+	if wait > 0 {
+		r, err := c.Wait(wait)
+		if err != nil {
+			return "", "", nil, err
+		}
+		results = rest.MergeResult(results, r)
+	}
+	return fmt.Sprintf("%s.%s", sub, obj), state, results, nil
+}
+
+// SetRetries overrides retry.retries for this run
+func (h *Hctl) SetRetries(retries int) {
+	h.cfg.Retry.Retries = retries
 }
 
 func (h *Hctl) SetLogging(level string) error {
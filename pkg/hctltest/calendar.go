@@ -0,0 +1,120 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendarsHandler serves /calendars, listing all calendar entities
+func calendarsHandler(t testing.TB, states *stateStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		var all []map[string]any
+		if err := json.Unmarshal(states.all(), &all); err != nil {
+			t.Errorf("Error unmarshal: %v", err)
+		}
+
+		calendars := []map[string]any{}
+		for _, s := range all {
+			id := fmt.Sprint(s["entity_id"])
+			if !strings.HasPrefix(id, "calendar.") {
+				continue
+			}
+			name := id
+			if attrs, ok := s["attributes"].(map[string]any); ok {
+				if n, ok := attrs["friendly_name"].(string); ok {
+					name = n
+				}
+			}
+			calendars = append(calendars, map[string]any{"entity_id": id, "name": name})
+		}
+
+		data, err := json.Marshal(calendars)
+		if err != nil {
+			t.Errorf("Error marshal: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
+
+// calendarEventsHandler serves /calendars/{entity_id} from testdata/calendar_events.json,
+// returning the events overlapping the start and end query parameters
+func calendarEventsHandler(t testing.TB, testdir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var calendars map[string][]map[string]any
+		if err := readTestdata(testdir, "calendar_events.json", &calendars); err != nil {
+			t.Errorf("Error reading calendar events: %v", err)
+		}
+
+		events, ok := calendars[r.PathValue("entity_id")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			if _, err := w.Write([]byte(`{"message": "Entity not found"}`)); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+
+		start, errStart := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+		end, errEnd := time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+		if errStart != nil || errEnd != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			if _, err := w.Write([]byte(`{"message": "Unable to parse start or end"}`)); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+
+		result := []map[string]any{}
+		for _, e := range events {
+			if eventTime(t, e["end"]).After(start) && eventTime(t, e["start"]).Before(end) {
+				result = append(result, e)
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Errorf("Error marshal: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
+
+func eventTime(t testing.TB, v any) time.Time {
+	m, _ := v.(map[string]any)
+	if dt, ok := m["dateTime"].(string); ok {
+		ts, err := time.Parse(time.RFC3339, dt)
+		if err != nil {
+			t.Errorf("Error parsing time: %v", err)
+		}
+		return ts
+	}
+	ts, err := time.ParseInLocation(time.DateOnly, fmt.Sprint(m["date"]), time.Local)
+	if err != nil {
+		t.Errorf("Error parsing date: %v", err)
+	}
+	return ts
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// imageHandler serves a small JPEG for every camera and image entity of the mock server
+func imageHandler(t testing.TB, states *stateStore, domain string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("entity_id")
+		states.mu.Lock()
+		found := states.find(id) != nil
+		states.mu.Unlock()
+		if !found || !strings.HasPrefix(id, domain+".") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+		for x := range 4 {
+			for y := range 3 {
+				img.Set(x, y, color.RGBA{R: uint8(x * 60), G: uint8(y * 80), B: 128, A: 255})
+			}
+		}
+		// cameras send JPEG, image entities PNG
+		var buf bytes.Buffer
+		contentType := "image/jpeg"
+		if domain == "image" {
+			contentType = "image/png"
+			if err := png.Encode(&buf, img); err != nil {
+				t.Errorf("Error encoding image: %v", err)
+			}
+		} else if err := jpeg.Encode(&buf, img, nil); err != nil {
+			t.Errorf("Error encoding image: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
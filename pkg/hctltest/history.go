@@ -0,0 +1,80 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// historyHandler serves /history/period/{timestamp} from testdata/history.json,
+// filtered by filter_entity_id and the time window like Home Assistant does
+func historyHandler(t testing.TB, testdir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var history map[string][]map[string]any
+		if err := readTestdata(testdir, "history.json", &history); err != nil {
+			t.Errorf("Error reading history: %v", err)
+		}
+
+		start, err := time.Parse(time.RFC3339, r.PathValue("timestamp"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		end := time.Now()
+		if e := r.URL.Query().Get("end_time"); e != "" {
+			if end, err = time.Parse(time.RFC3339, e); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		_, minimal := r.URL.Query()["minimal_response"]
+
+		result := [][]map[string]any{}
+		for _, id := range strings.Split(r.URL.Query().Get("filter_entity_id"), ",") {
+			var states []map[string]any
+			for _, s := range history[id] {
+				changed, err := time.Parse(time.RFC3339, s["last_changed"].(string))
+				if err != nil {
+					t.Errorf("Error parsing time: %v", err)
+				}
+				if changed.Before(start) || changed.After(end) {
+					continue
+				}
+				states = append(states, s)
+			}
+			if minimal {
+				for i := 1; i < len(states)-1; i++ {
+					states[i] = map[string]any{"state": states[i]["state"], "last_changed": states[i]["last_changed"]}
+				}
+			}
+			if len(states) > 0 {
+				result = append(result, states)
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Errorf("Error marshal: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
@@ -41,6 +41,34 @@ func MockServer(t testing.TB) *httptest.Server {
 		}
 	})
 
+	// WebSocket API
+	mux.HandleFunc("/websocket", webSocketHandler(t, testdir))
+
+	// log of the current run
+	mux.HandleFunc("/error_log", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		data, err := os.ReadFile(fmt.Sprintf("%s/testdata/error_log.txt", testdir))
+		if err != nil {
+			t.Errorf("Error reading file: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// hub configuration, e.g. version and unit system
+	mux.HandleFunc("/config", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		data, err := os.ReadFile(fmt.Sprintf("%s/testdata/config.json", testdir))
+		if err != nil {
+			t.Errorf("Error reading file: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
 	// get all services
 	mux.HandleFunc("/services", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -53,10 +81,20 @@ func MockServer(t testing.TB) *httptest.Server {
 		}
 	})
 
-	// get all states
-	mux.HandleFunc("/states", func(w http.ResponseWriter, _ *http.Request) {
+	states := newStateStore(t, testdir)
+
+	// history of entities from the recorder
+	//nolint:govet
+	mux.HandleFunc("GET /history/period/{timestamp}", historyHandler(t, testdir))
+
+	// logbook entries
+	//nolint:govet
+	mux.HandleFunc("GET /logbook/{timestamp}", logbookHandler(t, testdir))
+
+	// list event types with listeners
+	mux.HandleFunc("/events", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		data, err := os.ReadFile(fmt.Sprintf("%s/testdata/states.json", testdir))
+		data, err := os.ReadFile(fmt.Sprintf("%s/testdata/events.json", testdir))
 		if err != nil {
 			t.Errorf("Error reading file: %v", err)
 		}
@@ -65,6 +103,70 @@ func MockServer(t testing.TB) *httptest.Server {
 		}
 	})
 
+	// fire event, data has to be a JSON object if given
+	//nolint:govet
+	mux.HandleFunc("POST /events/{event_type}", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Error reading body: %v", err)
+		}
+		if len(body) > 0 {
+			var m map[string]any
+			if err := json.Unmarshal(body, &m); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				if _, err := w.Write([]byte(`{"message": "Event data should be a JSON object"}`)); err != nil {
+					t.Errorf("Error writing data: %v", err)
+				}
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprintf(w, `{"message": "Event %s fired."}`, r.PathValue("event_type")); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// camera snapshots and images
+	//nolint:govet
+	mux.HandleFunc("GET /camera_proxy/{entity_id}", imageHandler(t, states, "camera"))
+	//nolint:govet
+	mux.HandleFunc("GET /image_proxy/{entity_id}", imageHandler(t, states, "image"))
+
+	// calendars and their events
+	mux.HandleFunc("/calendars", calendarsHandler(t, states))
+	//nolint:govet
+	mux.HandleFunc("GET /calendars/{entity_id}", calendarEventsHandler(t, testdir))
+
+	// render templates
+	//nolint:govet
+	mux.HandleFunc("POST /template", templateHandler(t, states))
+
+	// get all states
+	mux.HandleFunc("/states", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(states.all()); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// get single state
+	//nolint:govet
+	mux.HandleFunc("GET /states/{entity_id}", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := states.get(r.PathValue("entity_id"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if _, err := w.Write([]byte(`{"message": "Entity not found."}`)); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
 	// Update entity in domain/service
 	//nolint:govet
 	mux.HandleFunc("POST /services/{domain}/{service}", func(w http.ResponseWriter, r *http.Request) {
@@ -77,11 +179,21 @@ func MockServer(t testing.TB) *httptest.Server {
 		if err := json.Unmarshal(body, &m); err != nil {
 			t.Errorf("Error Unmarshal: %v", err)
 		}
-		l := strings.Split(m["entity_id"].(string), ".")
-		name := l[1]
-		data, err := os.ReadFile(fmt.Sprintf("%s/testdata/%s_%s_%s_response.json", testdir, name, r.PathValue("domain"), r.PathValue("service")))
-		if err != nil {
-			t.Errorf("Error reading file: %v", err)
+		if changed, ok := states.apply(r.PathValue("service"), m); ok {
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(changed); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+		// fall back to a recorded response, or report no change at all
+		data := []byte("[]")
+		if ids := entityIDs(m); len(ids) == 1 {
+			_, name, _ := strings.Cut(ids[0], ".")
+			f := fmt.Sprintf("%s/testdata/%s_%s_%s_response.json", testdir, name, r.PathValue("domain"), r.PathValue("service"))
+			if d, err := os.ReadFile(f); err == nil {
+				data = d
+			}
 		}
 		w.WriteHeader(http.StatusOK)
 		if _, err := w.Write(data); err != nil {
@@ -0,0 +1,71 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// logbookHandler serves /logbook/{timestamp} from testdata/logbook.json,
+// filtered by entity and the time window like Home Assistant does
+func logbookHandler(t testing.TB, testdir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var entries []map[string]any
+		if err := readTestdata(testdir, "logbook.json", &entries); err != nil {
+			t.Errorf("Error reading logbook: %v", err)
+		}
+
+		start, err := time.Parse(time.RFC3339, r.PathValue("timestamp"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		end := start.Add(24 * time.Hour)
+		if e := r.URL.Query().Get("end_time"); e != "" {
+			if end, err = time.Parse(time.RFC3339, e); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		entity := r.URL.Query().Get("entity")
+
+		result := []map[string]any{}
+		for _, e := range entries {
+			when, err := time.Parse(time.RFC3339Nano, e["when"].(string))
+			if err != nil {
+				t.Errorf("Error parsing time: %v", err)
+			}
+			if when.Before(start) || when.After(end) {
+				continue
+			}
+			if entity != "" && e["entity_id"] != entity {
+				continue
+			}
+			result = append(result, e)
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Errorf("Error marshal: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
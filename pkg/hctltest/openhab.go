@@ -0,0 +1,211 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// OpenHABMockServer returns a mock of the openHAB REST API (served at / instead of /rest)
+// Commands sent to items are applied like openHAB does with autoupdate enabled
+func OpenHABMockServer(t testing.TB) *httptest.Server {
+	_, filename, _, _ := runtime.Caller(0)
+	testdir := filepath.Dir(filename)
+	t.Helper()
+	mux := http.NewServeMux()
+	items := newItemStore(t, testdir)
+
+	// handle default entry point and return the REST API version
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"version": "8", "locale": "en_US", "runtimeInfo": {"version": "4.2.0", "buildString": "Release Build"}}`)); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// get all items
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(items.all()); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// get single item
+	//nolint:govet
+	mux.HandleFunc("GET /items/{name}", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := items.get(r.PathValue("name"))
+		if !ok {
+			itemNotFound(t, w, r.PathValue("name"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	})
+
+	// send command to item
+	//nolint:govet
+	mux.HandleFunc("POST /items/{name}", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Error reading body: %v", err)
+		}
+		found, ok := items.command(r.PathValue("name"), string(body))
+		if !found {
+			itemNotFound(t, w, r.PathValue("name"))
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func itemNotFound(t testing.TB, w http.ResponseWriter, name string) {
+	w.WriteHeader(http.StatusNotFound)
+	msg := fmt.Sprintf(`{"error": {"message": "Item %s does not exist!", "http-code": 404}}`, name)
+	if _, err := w.Write([]byte(msg)); err != nil {
+		t.Errorf("Error writing data: %v", err)
+	}
+}
+
+// itemStore holds the items of the openHAB mock server
+type itemStore struct {
+	t     testing.TB
+	mu    sync.Mutex
+	items []map[string]any
+}
+
+func newItemStore(t testing.TB, testdir string) *itemStore {
+	s := &itemStore{t: t}
+	if err := readTestdata(testdir, "openhab_items.json", &s.items); err != nil {
+		t.Errorf("Error reading items: %v", err)
+	}
+	return s
+}
+
+func (s *itemStore) all() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(s.items)
+	if err != nil {
+		s.t.Errorf("Error marshal: %v", err)
+	}
+	return data
+}
+
+func (s *itemStore) find(name string) map[string]any {
+	for _, item := range s.items {
+		if item["name"] == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *itemStore) get(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.find(name)
+	if item == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		s.t.Errorf("Error marshal: %v", err)
+	}
+	return data, true
+}
+
+func isPercent(cmd string) bool {
+	p, err := strconv.Atoi(cmd)
+	return err == nil && p >= 0 && p <= 100
+}
+
+// command applies cmd to the item, found is false for unknown items
+// and ok is false for commands the item type does not accept
+func (s *itemStore) command(name string, cmd string) (bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.find(name)
+	if item == nil {
+		return false, false
+	}
+	state, _ := item["state"].(string)
+	itemType, _, _ := strings.Cut(item["type"].(string), ":")
+
+	switch {
+	case itemType == "Switch" && (cmd == "ON" || cmd == "OFF"):
+		state = cmd
+	case itemType == "Dimmer" && cmd == "ON":
+		state = "100"
+	case itemType == "Dimmer" && cmd == "OFF":
+		state = "0"
+	case itemType == "Dimmer" && isPercent(cmd):
+		state = cmd
+	case itemType == "Color" && (cmd == "ON" || cmd == "OFF" || isPercent(cmd)):
+		hsb := strings.Split(state, ",")
+		switch cmd {
+		case "ON":
+			hsb[2] = "100"
+		case "OFF":
+			hsb[2] = "0"
+		default:
+			hsb[2] = cmd
+		}
+		state = strings.Join(hsb, ",")
+	case itemType == "Color" && len(strings.Split(cmd, ",")) == 3:
+		state = cmd
+	case itemType == "Rollershutter" && cmd == "UP":
+		state = "0"
+	case itemType == "Rollershutter" && cmd == "DOWN":
+		state = "100"
+	case itemType == "Rollershutter" && cmd == "STOP":
+	case itemType == "Rollershutter" && isPercent(cmd):
+		state = cmd
+	case itemType == "Player" && (cmd == "PLAY" || cmd == "PAUSE"):
+		state = cmd
+	case itemType == "Number":
+		if _, err := strconv.ParseFloat(cmd, 64); err != nil {
+			return true, false
+		}
+		// keep the unit of the item
+		if _, unit, ok := strings.Cut(state, " "); ok {
+			cmd = fmt.Sprintf("%s %s", cmd, unit)
+		}
+		state = cmd
+	default:
+		return true, false
+	}
+	item["state"] = state
+	return true, true
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stateStore holds the states of the mock server, so service calls
+// are reflected when states are requested afterwards
+type stateStore struct {
+	t      testing.TB
+	mu     sync.Mutex
+	states []map[string]any
+}
+
+func newStateStore(t testing.TB, testdir string) *stateStore {
+	s := &stateStore{t: t}
+	if err := readTestdata(testdir, "states.json", &s.states); err != nil {
+		t.Errorf("Error reading states: %v", err)
+	}
+	return s
+}
+
+func (s *stateStore) all() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(s.states)
+	if err != nil {
+		s.t.Errorf("Error marshal: %v", err)
+	}
+	return data
+}
+
+func (s *stateStore) find(entityID string) map[string]any {
+	for _, state := range s.states {
+		if state["entity_id"] == entityID {
+			return state
+		}
+	}
+	return nil
+}
+
+func (s *stateStore) get(entityID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.find(entityID)
+	if state == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		s.t.Errorf("Error marshal: %v", err)
+	}
+	return data, true
+}
+
+// toFloat handles numbers sent as string (e.g. volume_level) as well
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// entityIDs returns entity_id of a service call payload, which is either a string or a list
+func entityIDs(payload map[string]any) []string {
+	switch v := payload["entity_id"].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var ids []string
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+// apply simulates the outcome of a service call and returns the changed states
+// like Home Assistant does, handled is false for services not simulated here
+func (s *stateStore) apply(service string, payload map[string]any) ([]byte, bool) {
+	ids := entityIDs(payload)
+	if len(ids) == 0 {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := []map[string]any{}
+	for _, entityID := range ids {
+		state := s.find(entityID)
+		if state == nil {
+			return nil, false
+		}
+		before, err := json.Marshal(state)
+		if err != nil {
+			s.t.Errorf("Error marshal: %v", err)
+		}
+		if !s.applyState(service, state, payload) {
+			return nil, false
+		}
+		after, err := json.Marshal(state)
+		if err != nil {
+			s.t.Errorf("Error marshal: %v", err)
+		}
+		if !bytes.Equal(before, after) {
+			changed = append(changed, state)
+		}
+	}
+
+	data, err := json.Marshal(changed)
+	if err != nil {
+		s.t.Errorf("Error marshal: %v", err)
+	}
+	return data, true
+}
+
+// applyState applies service to a single state, false for services not simulated here
+func (s *stateStore) applyState(service string, state map[string]any, payload map[string]any) bool {
+	attrs, _ := state["attributes"].(map[string]any)
+	// the state of a scene is the time it was last activated
+	if id, _ := state["entity_id"].(string); strings.HasPrefix(id, "scene.") {
+		if service != "turn_on" {
+			return false
+		}
+		state["state"] = time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+		return true
+	}
+	// scripts run in the background, so only the time they were started changes
+	if id, _ := state["entity_id"].(string); strings.HasPrefix(id, "script.") {
+		if service != "turn_on" {
+			return false
+		}
+		attrs["last_triggered"] = time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+		return true
+	}
+	switch service {
+	case "lock":
+		state["state"] = "locked"
+	case "unlock":
+		state["state"] = "unlocked"
+	case "alarm_arm_away":
+		state["state"] = "armed_away"
+	case "alarm_arm_home":
+		state["state"] = "armed_home"
+	case "alarm_disarm":
+		state["state"] = "disarmed"
+	case "open_cover":
+		state["state"] = "open"
+		if _, ok := attrs["current_position"]; ok {
+			attrs["current_position"] = 100.0
+		}
+	case "close_cover":
+		state["state"] = "closed"
+		if _, ok := attrs["current_position"]; ok {
+			attrs["current_position"] = 0.0
+		}
+	case "stop_cover":
+	case "set_cover_position":
+		if v, ok := toFloat(payload["position"]); ok {
+			attrs["current_position"] = v
+			state["state"] = "open"
+			if v == 0 {
+				state["state"] = "closed"
+			}
+		}
+	case "set_cover_tilt_position":
+		if v, ok := toFloat(payload["tilt_position"]); ok {
+			attrs["current_tilt_position"] = v
+		}
+	case "set_percentage":
+		if v, ok := toFloat(payload["percentage"]); ok {
+			attrs["percentage"] = v
+			state["state"] = "on"
+			if v == 0 {
+				// like many fans, report no percentage when off
+				attrs["percentage"] = nil
+				state["state"] = "off"
+			}
+		}
+	case "set_preset_mode":
+		attrs["preset_mode"] = payload["preset_mode"]
+		state["state"] = "on"
+	case "oscillate":
+		attrs["oscillating"] = payload["oscillating"]
+	case "set_direction":
+		attrs["direction"] = payload["direction"]
+	case "press":
+		// the state of a button is the time it was last pressed
+		state["state"] = time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+	case "trigger":
+		attrs["last_triggered"] = time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+	case "turn_on":
+		// like real media players, which are idle until something plays
+		if id, _ := state["entity_id"].(string); strings.HasPrefix(id, "media_player.") {
+			if state["state"] == "off" {
+				state["state"] = "idle"
+			}
+			break
+		}
+		state["state"] = "on"
+		if b, ok := toFloat(payload["brightness"]); ok {
+			attrs["brightness"] = b
+		}
+	case "turn_off":
+		state["state"] = "off"
+	case "toggle":
+		if state["state"] == "on" {
+			state["state"] = "off"
+		} else {
+			state["state"] = "on"
+		}
+	case "volume_set":
+		if v, ok := toFloat(payload["volume_level"]); ok {
+			attrs["volume_level"] = v
+		}
+	case "set_temperature":
+		if v, ok := toFloat(payload["temperature"]); ok {
+			attrs["temperature"] = v
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var statesExpr = regexp.MustCompile(`\{\{\s*states\(\s*['"]([^'"]+)['"]\s*\)\s*\}\}`)
+
+// templateHandler renders {{ states('entity_id') }} from the states of the mock server,
+// anything else left in braces is reported as error like Home Assistant does
+func templateHandler(t testing.TB, states *stateStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Template string `json:"template"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Error decoding body: %v", err)
+		}
+
+		rendered := statesExpr.ReplaceAllStringFunc(payload.Template, func(expr string) string {
+			states.mu.Lock()
+			defer states.mu.Unlock()
+			if s := states.find(statesExpr.FindStringSubmatch(expr)[1]); s != nil {
+				return fmt.Sprint(s["state"])
+			}
+			return "unknown"
+		})
+		if strings.Contains(rendered, "{{") || strings.Contains(rendered, "{%") {
+			w.WriteHeader(http.StatusBadRequest)
+			if _, err := w.Write([]byte(`{"message": "Error rendering template: TemplateSyntaxError: unexpected '}'"}`)); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(rendered)); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hctltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// InvalidToken is rejected by the fake WebSocket API with auth_invalid
+const InvalidToken = "invalid_token"
+
+type wsMessage struct {
+	ID          int            `json:"id"`
+	Type        string         `json:"type"`
+	AccessToken string         `json:"access_token"`
+	EventType   string         `json:"event_type"`
+	Domain      string         `json:"domain"`
+	Service     string         `json:"service"`
+	ServiceData map[string]any `json:"service_data"`
+	Target      map[string]any `json:"target"`
+}
+
+type wsEvent struct {
+	EventType string `json:"event_type"`
+}
+
+// webSocketHandler fakes the Home Assistant WebSocket API
+//
+// Supported commands: get_states, subscribe_events, unsubscribe_events,
+// call_service and ping. On subscribe_events all matching events from
+// testdata/state_changed_events.json are sent right after the result.
+func webSocketHandler(t testing.TB, testdir string) http.HandlerFunc {
+	upgrader := websocket.Upgrader{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Error upgrading connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.WriteJSON(map[string]any{"type": "auth_required", "ha_version": "2024.10.0"}); err != nil {
+			t.Errorf("Error writing data: %v", err)
+			return
+		}
+
+		var auth wsMessage
+		if err := conn.ReadJSON(&auth); err != nil {
+			return
+		}
+		if auth.Type != "auth" || auth.AccessToken == "" || auth.AccessToken == InvalidToken {
+			if err := conn.WriteJSON(map[string]any{"type": "auth_invalid", "message": "Invalid access token or password"}); err != nil {
+				t.Errorf("Error writing data: %v", err)
+			}
+			return
+		}
+		if err := conn.WriteJSON(map[string]any{"type": "auth_ok", "ha_version": "2024.10.0"}); err != nil {
+			t.Errorf("Error writing data: %v", err)
+			return
+		}
+
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if err := handleWebSocketMessage(conn, testdir, msg); err != nil {
+				t.Errorf("Error handling websocket message: %v", err)
+				return
+			}
+		}
+	}
+}
+
+func wsResult(id int, result any) map[string]any {
+	return map[string]any{"id": id, "type": "result", "success": true, "result": result}
+}
+
+func wsError(id int, code string, message string) map[string]any {
+	return map[string]any{"id": id, "type": "result", "success": false, "error": map[string]string{"code": code, "message": message}}
+}
+
+func readTestdata(testdir string, name string, v any) error {
+	data, err := os.ReadFile(fmt.Sprintf("%s/testdata/%s", testdir, name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func handleWebSocketMessage(conn *websocket.Conn, testdir string, msg wsMessage) error {
+	switch msg.Type {
+	case "ping":
+		return conn.WriteJSON(map[string]any{"id": msg.ID, "type": "pong"})
+	case "get_states":
+		var states []any
+		if err := readTestdata(testdir, "states.json", &states); err != nil {
+			return err
+		}
+		return conn.WriteJSON(wsResult(msg.ID, states))
+	case "unsubscribe_events":
+		return conn.WriteJSON(wsResult(msg.ID, nil))
+	case "subscribe_events":
+		if err := conn.WriteJSON(wsResult(msg.ID, nil)); err != nil {
+			return err
+		}
+		var events []json.RawMessage
+		if err := readTestdata(testdir, "state_changed_events.json", &events); err != nil {
+			return err
+		}
+		for _, raw := range events {
+			var e wsEvent
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return err
+			}
+			if msg.EventType != "" && msg.EventType != e.EventType {
+				continue
+			}
+			if err := conn.WriteJSON(map[string]any{"id": msg.ID, "type": "event", "event": raw}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "call_service":
+		var services []struct {
+			Domain   string         `json:"domain"`
+			Services map[string]any `json:"services"`
+		}
+		if err := readTestdata(testdir, "services.json", &services); err != nil {
+			return err
+		}
+		for _, s := range services {
+			if _, ok := s.Services[msg.Service]; ok && s.Domain == msg.Domain {
+				return conn.WriteJSON(wsResult(msg.ID, map[string]any{
+					"context":  map[string]any{"id": "ABDCDEFGHIJKLMNOPQRSTUVW99", "parent_id": nil, "user_id": nil},
+					"response": nil,
+				}))
+			}
+		}
+		return conn.WriteJSON(wsError(msg.ID, "not_found", fmt.Sprintf("Service %s.%s not found.", msg.Domain, msg.Service)))
+	default:
+		return conn.WriteJSON(wsError(msg.ID, "unknown_command", "Unknown command."))
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// GetHistory returns the recorded states of entities, one list per entity
+func (h *Hctl) GetHistory(entities []string, opts rest.HistoryOptions) ([][]rest.HassState, error) {
+	c, err := h.GetHass("history")
+	if err != nil {
+		return nil, err
+	}
+	return c.GetHistory(entities, opts)
+}
+
+// Resample returns width values of the numeric states between since and until, each being the
+// state in effect at the middle of its slot (NaN before the first numeric state)
+// ok is false if none of the states is numeric
+func Resample(states []rest.HassState, since time.Time, until time.Time, width int) ([]float64, bool) {
+	type point struct {
+		at    time.Time
+		value float64
+	}
+	var points []point
+	for _, s := range states {
+		v, err := strconv.ParseFloat(s.State, 64)
+		if err != nil {
+			// e.g. unavailable or unknown
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, s.LastChanged)
+		if err != nil {
+			continue
+		}
+		points = append(points, point{at, v})
+	}
+	if len(points) == 0 || width <= 0 {
+		return nil, false
+	}
+
+	slot := until.Sub(since) / time.Duration(width)
+	values := make([]float64, width)
+	var j int
+	for i := range values {
+		at := since.Add(slot*time.Duration(i) + slot/2)
+		for j < len(points) && !points[j].at.After(at) {
+			j++
+		}
+		if j == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = points[j-1].value
+	}
+	return values, true
+}
@@ -24,8 +24,11 @@ import (
 	u "net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 
+	"github.com/xx4h/hctl/pkg/client"
 	config "github.com/xx4h/hctl/pkg/config"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -45,7 +48,16 @@ func InitializeConfig(c *config.Config, configPath string) error {
 
 	hub := getMinimalConfig()
 
-	if err := testAPI(hub.URL, hub.Token); err != nil {
+	// use transport settings (tls, timeout, proxy) from environment or an existing config
+	hub.TLS = c.Hub.TLS
+	hub.Timeout = c.Hub.Timeout
+	hub.Proxy = c.Hub.Proxy
+	httpClient, err := client.New(hub)
+	if err != nil {
+		return err
+	}
+
+	if err := testAPI(httpClient, hub.Type, hub.URL, hub.Token); err != nil {
 		log.Error().Msgf("API test failed: %v", err)
 		return InitializeConfig(c, configPath)
 	}
@@ -66,8 +78,8 @@ func InitializeConfig(c *config.Config, configPath string) error {
 func getMinimalConfig() *config.Hub {
 	hub := new(config.Hub)
 	hub.Type = getHubType()
-	hub.URL = getURL()
-	hub.Token = getToken()
+	hub.URL = getURL(hub.Type)
+	hub.Token = getToken(hub.Type)
 
 	return hub
 }
@@ -85,18 +97,16 @@ func configExists(config string) (bool, error) {
 
 func getHubType() string {
 	hubType := "hass"
-	// TODO: Enable as soon as we have more supported hub types
-	// supported := []string{"hass"}
-	// fmt.Printf("Which Hub Type are you using? (Supported: hass) [%s]: ", hubType)
-	// _, err := fmt.Scanln(&hubType)
-	// if err != nil && err.Error() != "unexpected newline" {
-	// 	fmt.Printf("Error: %v\n", err)
-	// 	return getHubType()
-	// }
-	// if !slices.Contains(supported, hubType) {
-	// 	fmt.Printf("Unsupported Hub Type: %s\n", hubType)
-	// 	return getHubType()
-	// }
+	fmt.Printf("Which Hub Type are you using? (Supported: %s) [%s]: ", strings.Join(config.HubTypes, ", "), hubType)
+	_, err := fmt.Scanln(&hubType)
+	if err != nil && err.Error() != "unexpected newline" {
+		fmt.Printf("Error: %v\n", err)
+		return getHubType()
+	}
+	if !slices.Contains(config.HubTypes, hubType) {
+		fmt.Printf("Unsupported Hub Type: %s\n", hubType)
+		return getHubType()
+	}
 	return hubType
 }
 
@@ -108,17 +118,22 @@ func isURL(url string) bool {
 	return true
 }
 
-func getURL() string {
+var exampleURLs = map[string]string{
+	"hass":    "https://home-assistant.example.com/api",
+	"openhab": "https://openhab.example.com/rest",
+}
+
+func getURL(hubType string) string {
 	var url string
-	fmt.Print("Enter API URL of your hub (e.g. https://home-assistant.example.com/api): ")
+	fmt.Printf("Enter API URL of your hub (e.g. %s): ", exampleURLs[hubType])
 	_, err := fmt.Scanln(&url)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		return getURL()
+		return getURL(hubType)
 	}
 	if ok := isURL(url); !ok {
 		fmt.Printf("Not a valid URL: %s\n", url)
-		return getURL()
+		return getURL(hubType)
 	}
 	return url
 }
@@ -129,22 +144,25 @@ func isJwtToken(token []byte) (bool, error) {
 	return err == nil, err
 }
 
-func getToken() string {
+func getToken(hubType string) string {
 	fmt.Print("Enter your hub token: ")
 	byteToken, err := term.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		return getToken()
+		return getToken(hubType)
+	}
+	// openHAB API tokens (oh.name.secret) are no JWT, and may be omitted for anonymous access
+	if hubType != "hass" {
+		return string(byteToken)
 	}
 	if ok, err := isJwtToken(byteToken); !ok {
 		fmt.Printf("\nNot a valid Token (JWT): %v\n", err)
-		return getToken()
+		return getToken(hubType)
 	}
 	return string(byteToken)
 }
 
-func testAPI(url string, token string) error {
-	c := &http.Client{}
+func testAPI(c *http.Client, hubType string, url string, token string) error {
 	req, err := http.NewRequest("GET", url+"/", nil)
 	if err != nil {
 		return err
@@ -172,16 +190,21 @@ func testAPI(url string, token string) error {
 		return fmt.Errorf("unexpected API return code: %d", resp.StatusCode)
 	}
 
-	var a map[string]string
+	var a map[string]any
 	if err := json.Unmarshal(body, &a); err != nil {
 		log.Debug().Caller().Msgf("unexpected API return: %v", string(body))
 		return fmt.Errorf("unexpected API return, did you provide the correct API URL?")
 	}
 
-	v, ok := a["message"]
+	// Home Assistant answers with a message, openHAB with the version of its REST API
+	key := "message"
+	if hubType == "openhab" {
+		key = "version"
+	}
+	v, ok := a[key]
 	if !ok {
 		return fmt.Errorf("unexpected API return message: %v", a)
 	}
-	log.Info().Msgf("API Returned: %s", v)
+	log.Info().Msgf("API Returned: %v", v)
 	return nil
 }
@@ -0,0 +1,170 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// LogbookFilter selects the logbook entries printed by Logbook
+// Entities and domains work like in WatchFilter, text matches case-insensitive anywhere in the entry
+type LogbookFilter struct {
+	entities *WatchFilter
+	text     string
+}
+
+// LogbookOptions are the period and the polling of Logbook
+type LogbookOptions struct {
+	Since time.Time
+	Until time.Time
+	// Follow keeps polling for new entries every Interval until ctx is done
+	Follow   bool
+	Interval time.Duration
+	// Count stops after that many entries, if > 0
+	Count int
+}
+
+// NewLogbookFilter creates a filter from entity names or glob patterns, domains and a free text
+func (h *Hctl) NewLogbookFilter(names []string, domains []string, text string) (*LogbookFilter, error) {
+	c, err := h.GetHass("logbook")
+	if err != nil {
+		return nil, err
+	}
+	f, err := newWatchFilter(c, names, domains)
+	if err != nil {
+		return nil, err
+	}
+	return &LogbookFilter{entities: f, text: strings.ToLower(text)}, nil
+}
+
+func (f *LogbookFilter) Match(e rest.LogbookEntry) bool {
+	if e.EntityID != "" {
+		if !f.entities.Match(e.EntityID) {
+			return false
+		}
+	} else if len(f.entities.entities) > 0 || len(f.entities.patterns) > 0 ||
+		(len(f.entities.domains) > 0 && !slices.Contains(f.entities.domains, e.Domain)) {
+		// e.g. "Home Assistant started" has a domain, but no entity
+		return false
+	}
+	if f.text == "" {
+		return true
+	}
+	for _, s := range []string{e.Name, e.Message, e.EntityID, e.State, e.Cause(), e.ContextMessage} {
+		if strings.Contains(strings.ToLower(s), f.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// entity returns the entity to let the hub filter for, if the filter matches a single entity only
+func (f *LogbookFilter) entity() string {
+	if len(f.entities.entities) == 1 && len(f.entities.patterns) == 0 {
+		return f.entities.entities[0]
+	}
+	return ""
+}
+
+func logbookString(e rest.LogbookEntry) string {
+	when := e.When
+	if t, err := time.Parse(time.RFC3339Nano, e.When); err == nil {
+		when = t.Local().Format(time.DateTime)
+	}
+	subject := e.EntityID
+	if subject == "" {
+		subject = e.Domain
+	}
+	s := fmt.Sprintf("%s  %-40s %s", when, subject, strings.TrimSpace(fmt.Sprintf("%s %s", e.Name, e.Message)))
+	if cause := e.Cause(); cause != "" {
+		s = fmt.Sprintf("%s  (by %s)", s, cause)
+	}
+	return s
+}
+
+func logbookKey(e rest.LogbookEntry) string {
+	return strings.Join([]string{e.When, e.EntityID, e.Name, e.Message}, "\x00")
+}
+
+// Logbook prints the logbook entries matching filter in the period of opts
+// With opts.Follow it keeps printing new entries until ctx is done
+func (h *Hctl) Logbook(ctx context.Context, out io.Writer, filter *LogbookFilter, opts LogbookOptions) error {
+	c, err := h.GetHass("logbook")
+	if err != nil {
+		return err
+	}
+
+	since, until := opts.Since, opts.Until
+	// entries printed at the time of the last one, as the next poll starts there again
+	var last time.Time
+	seen := map[string]bool{}
+	var printed int
+	for {
+		entries, err := c.GetLogbook(filter.entity(), since, until)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			when, err := time.Parse(time.RFC3339Nano, e.When)
+			if err != nil {
+				return fmt.Errorf("invalid logbook entry time %s: %w", e.When, err)
+			}
+			if when.Before(last) || seen[logbookKey(e)] {
+				continue
+			}
+			if when.After(last) {
+				last = when
+				clear(seen)
+			}
+			seen[logbookKey(e)] = true
+			if !filter.Match(e) {
+				continue
+			}
+			if o.Structured() {
+				if err := o.FprintRecord(out, e); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(out, logbookString(e))
+			}
+			printed++
+			if opts.Count > 0 && printed >= opts.Count {
+				return nil
+			}
+		}
+		if !opts.Follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+		if !last.IsZero() {
+			// the API only takes whole seconds
+			since = last.Truncate(time.Second)
+		}
+		until = time.Now()
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// LogFilter selects the entries of the error log printed by Logs
+type LogFilter struct {
+	severity int
+	loggers  []string
+	pattern  *regexp.Regexp
+}
+
+// LogsOptions control how many entries Logs prints and whether it keeps polling
+type LogsOptions struct {
+	// Tail only prints the last n matching entries of the current log, if > 0
+	Tail int
+	// Follow keeps polling for new entries every Interval until ctx is done
+	Follow   bool
+	Interval time.Duration
+}
+
+// NewLogFilter creates a filter for entries of at least level, written by one of loggers
+// (integration names like mqtt or logger names) and matching pattern, empty values match all
+func NewLogFilter(level string, loggers []string, pattern string) (*LogFilter, error) {
+	f := &LogFilter{loggers: loggers}
+	if level != "" {
+		f.severity = slices.Index(rest.LogLevels, strings.ToUpper(level))
+		if f.severity < 0 {
+			return nil, fmt.Errorf("invalid level %s, use one of %s", level, strings.ToLower(strings.Join(rest.LogLevels, ", ")))
+		}
+	}
+	if pattern != "" {
+		rex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+		f.pattern = rex
+	}
+	return f, nil
+}
+
+// matchLogger matches the logger itself, its children and, for integration names,
+// e.g. homeassistant.components.mqtt.client or custom_components.mqtt for mqtt
+func matchLogger(logger string, name string) bool {
+	if logger == name || strings.HasPrefix(logger, name+".") {
+		return true
+	}
+	for _, prefix := range []string{"homeassistant.components.", "custom_components."} {
+		if logger == prefix+name || strings.HasPrefix(logger, prefix+name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *LogFilter) Match(e rest.LogEntry) bool {
+	if f.severity > 0 && e.Severity() < f.severity {
+		return false
+	}
+	if len(f.loggers) > 0 && !slices.ContainsFunc(f.loggers, func(name string) bool { return matchLogger(e.Logger, name) }) {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(e.String())
+}
+
+// newLogText returns the part of the log written since prev was fetched,
+// or the whole log if it was rotated in between (e.g. restart of the hub)
+func newLogText(prev string, cur string) string {
+	if strings.HasPrefix(cur, prev) {
+		return cur[len(prev):]
+	}
+	return cur
+}
+
+// fprintLogEntry prints e as written to the log, or as record
+func fprintLogEntry(out io.Writer, e rest.LogEntry) error {
+	if o.Structured() {
+		return o.FprintRecord(out, e)
+	}
+	fmt.Fprintln(out, e.String())
+	return nil
+}
+
+// Logs prints the entries of the error log matching filter
+// With opts.Follow it keeps printing new entries until ctx is done
+func (h *Hctl) Logs(ctx context.Context, out io.Writer, filter *LogFilter, opts LogsOptions) error {
+	c, err := h.GetHass("logs")
+	if err != nil {
+		return err
+	}
+
+	var prev string
+	// the last entry may still be written to (e.g. a traceback) while fetching the log
+	var last rest.LogEntry
+	var lastPrinted bool
+	tail := opts.Tail
+	for {
+		log, err := c.GetErrorLog()
+		if err != nil {
+			return err
+		}
+		parsed := rest.ParseErrorLog(newLogText(prev, log))
+
+		// lines without header continue the last entry of the previous fetch,
+		// unless the log has been rotated
+		if len(parsed) > 0 && parsed[0].Time == "" && last.Time != "" && strings.HasPrefix(log, prev) {
+			cont := parsed[0]
+			parsed = parsed[1:]
+			last.Message += "\n" + cont.Message
+			switch {
+			case lastPrinted:
+				// only the new lines, records carry the header of their entry
+				if o.Structured() {
+					cont.Time, cont.Level, cont.Thread, cont.Logger = last.Time, last.Level, last.Thread, last.Logger
+				}
+				if err := fprintLogEntry(out, cont); err != nil {
+					return err
+				}
+			case filter.Match(last):
+				// e.g. the pattern matches the new lines
+				if err := fprintLogEntry(out, last); err != nil {
+					return err
+				}
+				lastPrinted = true
+			}
+		}
+
+		var entries []rest.LogEntry
+		for _, e := range parsed {
+			if filter.Match(e) {
+				entries = append(entries, e)
+			}
+		}
+		if len(parsed) > 0 {
+			// a matching last entry is always printed, even with tail
+			last = parsed[len(parsed)-1]
+			lastPrinted = filter.Match(last)
+		}
+		prev = log
+		if tail > 0 && len(entries) > tail {
+			entries = entries[len(entries)-tail:]
+		}
+		// only limit the entries present when starting
+		tail = 0
+
+		for _, e := range entries {
+			if err := fprintLogEntry(out, e); err != nil {
+				return err
+			}
+		}
+		if !opts.Follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openhab
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// errNotSupported is returned for actions openHAB items have no equivalent for
+func errNotSupported(action string) error {
+	return fmt.Errorf("%s is not supported by the openHAB backend", action)
+}
+
+// setTarget records the state the last action is expected to result in
+func (h *OpenHAB) setTarget(domain, name, state string, attributes map[string]float64) {
+	h.Result = rest.HassResult{
+		EntityID:         fmt.Sprintf("%s.%s", domain, name),
+		TargetState:      state,
+		TargetAttributes: attributes,
+	}
+}
+
+// command sends command, the equivalent of service, to the item and returns its new state
+// openHAB does not report state changes for commands, but items are updated
+// right away (autoupdate), so the item is fetched again afterwards
+func (h *OpenHAB) command(domain string, name string, service string, command string) ([]rest.HassResult, error) {
+	entityID := fmt.Sprintf("%s.%s", domain, name)
+	if err := rest.ConfirmProtected(h.Protected, h.Confirm, fmt.Sprintf("%s.%s", domain, service), entityID); err != nil {
+		return nil, err
+	}
+	if err := h.SendCommand(name, command); err != nil {
+		return nil, err
+	}
+	i, err := h.GetItem(name)
+	if err != nil {
+		return nil, err
+	}
+	s := i.ToState()
+	r := rest.HassResult{EntityID: s.EntityID, State: s.State}
+	r.Attributes.FriendlyName, _ = s.Attributes["friendly_name"].(string)
+	return []rest.HassResult{r}, nil
+}
+
+func (h *OpenHAB) turn(state string, args []string) (string, string, string, []rest.HassResult, error) {
+	domain, name, err := h.resolve(args, fmt.Sprintf("turn_%s", state))
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	results, err := h.command(domain, name, "turn_"+state, strings.ToUpper(state))
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(domain, name, state, nil)
+	return name, state, domain, results, nil
+}
+
+func (h *OpenHAB) TurnOn(args ...string) (string, string, string, []rest.HassResult, error) {
+	return h.turn("on", args)
+}
+
+func (h *OpenHAB) TurnOff(args ...string) (string, string, string, []rest.HassResult, error) {
+	return h.turn("off", args)
+}
+
+// Toggle sends OFF to items that are on and ON to all others, as openHAB has no toggle command
+func (h *OpenHAB) Toggle(args ...string) (string, string, string, []rest.HassResult, error) {
+	domain, name, err := h.resolve(args, "toggle")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	i, err := h.GetItem(name)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	target := "on"
+	if i.ToState().State == "on" {
+		target = "off"
+	}
+	results, err := h.command(domain, name, "toggle", strings.ToUpper(target))
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(domain, name, target, nil)
+	return name, "toggle", domain, results, nil
+}
+
+// brightness returns the percentage (1-100) for 1-99, min, mid, max and +/-,
+// the latter stepping to the next multiple of 10 like the Home Assistant backend
+func brightness(i Item, value string) (int, error) {
+	switch value {
+	case "min":
+		return 1, nil
+	case "mid":
+		return 50, nil
+	case "max":
+		return 100, nil
+	case "+", "-":
+		cur, _ := i.percent()
+		p := int(math.Round(cur))
+		diff := p % 10
+		if value == "+" {
+			return min(p+(10-diff), 100), nil
+		}
+		if diff == 0 {
+			diff = 10
+		}
+		return max(p-diff, 1), nil
+	}
+	p, err := strconv.Atoi(value)
+	if err != nil || p < 1 || p > 100 {
+		return 0, fmt.Errorf("invalid brightness percentage: %s", value)
+	}
+	return p, nil
+}
+
+// rgbToHSB converts R,G,B to the h,s,b command of Color items
+func rgbToHSB(color string) (string, error) {
+	parts := strings.Split(color, ",")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("color must be in format R,G,B")
+	}
+	var rgb [3]float64
+	for n, part := range parts {
+		val, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || val < 0 || val > 255 {
+			return "", fmt.Errorf("invalid RGB value: %s", part)
+		}
+		rgb[n] = float64(val) / 255
+	}
+	r, g, b := rgb[0], rgb[1], rgb[2]
+	hi, lo := max(r, g, b), min(r, g, b)
+	d := hi - lo
+
+	var hue float64
+	switch {
+	case d == 0:
+		hue = 0
+	case hi == r:
+		hue = math.Mod((g-b)/d, 6)
+	case hi == g:
+		hue = (b-r)/d + 2
+	default:
+		hue = (r-g)/d + 4
+	}
+	hue *= 60
+	if hue < 0 {
+		hue += 360
+	}
+	var sat float64
+	if hi > 0 {
+		sat = d / hi
+	}
+	return fmt.Sprintf("%.0f,%.0f,%.0f", hue, sat*100, hi*100), nil
+}
+
+// TurnLightOnCustom sends a brightness to Dimmer and Color items, or a color to Color items
+// Color temperature and transitions are separate items/profiles in openHAB and not supported
+func (h *OpenHAB) TurnLightOnCustom(device, bright, color string, colorTemp int, transition float64) (string, string, string, []rest.HassResult, error) {
+	if colorTemp != 0 {
+		return "", "", "", nil, errNotSupported("color temperature")
+	}
+	if transition != 0 {
+		return "", "", "", nil, errNotSupported("transition")
+	}
+	if bright != "" && color != "" {
+		return "", "", "", nil, fmt.Errorf("cannot specify both brightness and color at the same time")
+	}
+
+	domain, name, err := h.resolve([]string{device}, "turn_on")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	i, err := h.GetItem(name)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	command := "ON"
+	target := map[string]float64{}
+	switch {
+	case color != "":
+		if i.baseType() != "Color" {
+			return "", "", "", nil, fmt.Errorf("item %s (%s) does not support color", name, i.Type)
+		}
+		if command, err = rgbToHSB(color); err != nil {
+			return "", "", "", nil, err
+		}
+	case bright != "":
+		if t := i.baseType(); t != "Dimmer" && t != "Color" {
+			return "", "", "", nil, fmt.Errorf("item %s (%s) does not support brightness", name, i.Type)
+		}
+		p, err := brightness(i, bright)
+		if err != nil {
+			return "", "", "", nil, err
+		}
+		command = strconv.Itoa(p)
+		target["brightness"] = toBrightness(float64(p))
+	}
+
+	results, err := h.command(domain, name, "turn_on", command)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(domain, name, "on", target)
+	return name, "on", domain, results, nil
+}
+
+func (h *OpenHAB) TurnLightOffTransition(device string, transition float64) (string, string, string, []rest.HassResult, error) {
+	if transition != 0 {
+		return "", "", "", nil, errNotSupported("transition")
+	}
+	return h.TurnOff(device)
+}
+
+// TemperatureSet sends temp to a setpoint item, in the unit of the item
+func (h *OpenHAB) TemperatureSet(obj string, temp float64) (string, string, string, []rest.HassResult, error) {
+	domain, name, err := h.resolve([]string{obj}, "set_temperature")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	tempStr := fmt.Sprintf("%.1f", temp)
+	results, err := h.command(domain, name, "set_temperature", tempStr)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	target, err := strconv.ParseFloat(tempStr, 64)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(domain, name, "", map[string]float64{"temperature": target})
+	return name, fmt.Sprintf("temperature set to %.1f", temp), domain, results, nil
+}
+
+// VolumeSet is not supported, as volume is a separate Dimmer item in openHAB
+func (h *OpenHAB) VolumeSet(_ string, _ int) (string, string, string, []rest.HassResult, error) {
+	return "", "", "", nil, errNotSupported("volume")
+}
+
+// PlayMusic is not supported, as openHAB has no generic way to play media urls
+func (h *OpenHAB) PlayMusic(_, _, _ string) (string, string, string, []rest.HassResult, error) {
+	return "", "", "", nil, errNotSupported("playing media")
+}
+
+// Wait blocks until the item of the last action reached its target state
+// and attributes, or fails when timeout has passed
+func (h *OpenHAB) Wait(timeout time.Duration) (rest.HassResult, error) {
+	res, err := rest.WaitFor(h.Result, timeout, h.FetchState)
+	if err == nil {
+		h.Result = res
+	}
+	return res, err
+}
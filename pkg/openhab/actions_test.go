@@ -0,0 +1,276 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openhab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+func Test_Actions(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	defer ms.Close()
+
+	tests := []struct {
+		name    string
+		action  func(h *OpenHAB) (string, error)
+		want    string
+		wantErr string
+	}{
+		{
+			name: "turn on switch",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, r, err := h.TurnOn("Garden_Pump")
+				return stateOf(r), err
+			},
+			want: "on",
+		},
+		{
+			name: "toggle switch",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, r, err := h.Toggle("Garden_Pump")
+				return stateOf(r), err
+			},
+			want: "off",
+		},
+		{
+			name: "dimmer brightness",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, r, err := h.TurnLightOnCustom("Kitchen_Dimmer", "40", "", 0, 0)
+				return stateOf(r), err
+			},
+			want: "on",
+		},
+		{
+			name: "dimmer brightness step",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TurnLightOnCustom("Kitchen_Dimmer", "+", "", 0, 0)
+				if err != nil {
+					return "", err
+				}
+				i, err := h.GetItem("Kitchen_Dimmer")
+				return i.State, err
+			},
+			want: "50",
+		},
+		{
+			name: "color",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TurnLightOnCustom("Bedroom_Color", "", "255,0,0", 0, 0)
+				if err != nil {
+					return "", err
+				}
+				i, err := h.GetItem("Bedroom_Color")
+				return i.State, err
+			},
+			want: "0,100,100",
+		},
+		{
+			name: "brightness of switch",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TurnLightOnCustom("Livingroom_Light", "40", "", 0, 0)
+				return "", err
+			},
+			wantErr: "does not support brightness",
+		},
+		{
+			name: "color temperature",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TurnLightOnCustom("Bedroom_Color", "", "", 3000, 0)
+				return "", err
+			},
+			wantErr: "color temperature is not supported by the openHAB backend",
+		},
+		{
+			name: "temperature",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TemperatureSet("Heating_Setpoint", 22.5)
+				if err != nil {
+					return "", err
+				}
+				i, err := h.GetItem("Heating_Setpoint")
+				return i.State, err
+			},
+			want: "22.5 °C",
+		},
+		{
+			name: "sensor has no turn_on",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.TurnOn("Front_Door")
+				return "", err
+			},
+			wantErr: "does not support turn_on",
+		},
+		{
+			name: "volume",
+			action: func(h *OpenHAB) (string, error) {
+				_, _, _, _, err := h.VolumeSet("Kitchen_Radio", 10)
+				return "", err
+			},
+			wantErr: "volume is not supported by the openHAB backend",
+		},
+	}
+
+	// run in order, as the mock server keeps state between tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(ms.URL, "", false, nil)
+			got, err := tt.action(h)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if _, err := h.Wait(time.Second); err != nil {
+				t.Errorf("wait: %v", err)
+			}
+		})
+	}
+}
+
+func stateOf(results []rest.HassResult) string {
+	if len(results) != 1 {
+		return ""
+	}
+	return results[0].State
+}
+
+func Test_rgbToHSB(t *testing.T) {
+	tests := map[string]string{
+		"255,0,0":     "0,100,100",
+		"0,255,0":     "120,100,100",
+		"0,0,255":     "240,100,100",
+		"255,255,255": "0,0,100",
+		"0,0,0":       "0,0,0",
+	}
+	for rgb, want := range tests {
+		t.Run(rgb, func(t *testing.T) {
+			got, err := rgbToHSB(rgb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func Test_CallService(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		domain   string
+		service  string
+		entities []string
+		data     map[string]any
+		item     string
+		want     string
+		wantErr  string
+	}{
+		"cover position": {
+			domain:   "cover",
+			service:  "set_cover_position",
+			entities: []string{"Office_Shutter"},
+			data:     map[string]any{"position": float64(30)},
+			item:     "Office_Shutter",
+			want:     "70",
+		},
+		"media play": {
+			domain:   "media_player",
+			service:  "media_play",
+			entities: []string{"Kitchen_Radio"},
+			item:     "Kitchen_Radio",
+			want:     "PLAY",
+		},
+		"unsupported data": {
+			domain:   "switch",
+			service:  "turn_on",
+			entities: []string{"Garden_Pump"},
+			data:     map[string]any{"flash": true},
+			wantErr:  "data flash is not supported by the openHAB backend for turn_on",
+		},
+		"no entities": {
+			domain:  "switch",
+			service: "turn_on",
+			wantErr: "switch.turn_on needs at least one entity",
+		},
+		"unknown service": {
+			domain:  "switch",
+			service: "reload",
+			wantErr: "domain switch has no service reload",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := New(ms.URL, "", false, nil)
+			_, err := h.CallService(tt.domain, tt.service, tt.entities, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			i, err := h.GetItem(tt.item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i.State != tt.want {
+				t.Errorf("got %s, want %s", i.State, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Actions_Protected(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	defer ms.Close()
+
+	h := New(ms.URL, "", false, nil)
+	h.Protected = []string{"switch.Garden_Pump"}
+	if _, _, _, _, err := h.TurnOn("Garden_Pump"); !errors.Is(err, rest.ErrNotConfirmed) {
+		t.Errorf("got error %v, want %v", err, rest.ErrNotConfirmed)
+	}
+	if i, err := h.GetItem("Garden_Pump"); err != nil || i.State == "ON" {
+		t.Errorf("got %s (%v), want the item unchanged", i.State, err)
+	}
+
+	h.Confirm = func(string, string) bool { return true }
+	if _, _, _, _, err := h.TurnOn("Garden_Pump"); err != nil {
+		t.Errorf("got error %v, want none", err)
+	}
+
+	h.Fuzz = true
+	if _, _, err := h.ResolveEntity("Garden_Pmp"); !errors.Is(err, rest.ErrNotExact) {
+		t.Errorf("got error %v, want %v", err, rest.ErrNotExact)
+	}
+}
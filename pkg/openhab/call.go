@@ -0,0 +1,138 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openhab
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// serviceData lists the data each service accepts
+var serviceData = map[string][]string{
+	"turn_on":            {"brightness_pct"},
+	"set_cover_position": {"position"},
+	"set_temperature":    {"temperature"},
+}
+
+func dataFloat(data map[string]any, key string) (float64, error) {
+	switch v := data[key].(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case nil:
+		return 0, fmt.Errorf("missing data: %s", key)
+	}
+	return 0, fmt.Errorf("invalid data %s: %v", key, data[key])
+}
+
+// serviceCommand returns the command for service, as described in domainServices
+func serviceCommand(i Item, service string, data map[string]any) (string, error) {
+	for k := range data {
+		if !slices.Contains(serviceData[service], k) {
+			return "", fmt.Errorf("data %s is not supported by the openHAB backend for %s", k, service)
+		}
+	}
+
+	switch service {
+	case "turn_on":
+		if _, ok := data["brightness_pct"]; !ok {
+			return "ON", nil
+		}
+		p, err := dataFloat(data, "brightness_pct")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%.0f", p), nil
+	case "turn_off":
+		return "OFF", nil
+	case "toggle":
+		if i.ToState().State == "on" {
+			return "OFF", nil
+		}
+		return "ON", nil
+	case "open_cover":
+		return "UP", nil
+	case "close_cover":
+		return "DOWN", nil
+	case "stop_cover":
+		return "STOP", nil
+	case "set_cover_position":
+		p, err := dataFloat(data, "position")
+		if err != nil {
+			return "", err
+		}
+		// openHAB counts from 0 (open) to 100 (closed)
+		return fmt.Sprintf("%.0f", 100-p), nil
+	case "media_play":
+		return "PLAY", nil
+	case "media_pause":
+		return "PAUSE", nil
+	case "set_temperature":
+		t, err := dataFloat(data, "temperature")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%.1f", t), nil
+	}
+	return "", errNotSupported(service)
+}
+
+// CallService sends the command matching domain.service to all entities
+// Returns the new states of the items
+func (h *OpenHAB) CallService(domain string, service string, entities []string, data map[string]any) ([]rest.HassResult, error) {
+	svcs, ok := domainServices[domain]
+	if !ok {
+		return nil, fmt.Errorf("domain %s does not exist", domain)
+	}
+	if _, ok := svcs[service]; !ok {
+		return nil, fmt.Errorf("domain %s has no service %s", domain, service)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("%s.%s needs at least one entity", domain, service)
+	}
+
+	var results []rest.HassResult
+	for _, e := range entities {
+		// items of other domains can't handle the commands of this domain
+		if !strings.Contains(e, ".") {
+			e = fmt.Sprintf("%s.%s", domain, e)
+		}
+		d, name, err := h.resolve([]string{e}, service)
+		if err != nil {
+			return nil, err
+		}
+		i, err := h.GetItem(name)
+		if err != nil {
+			return nil, err
+		}
+		command, err := serviceCommand(i, service, data)
+		if err != nil {
+			return nil, err
+		}
+		r, err := h.command(d, name, service, command)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openhab is a backend for the openHAB REST API
+// Items are mapped onto the Home Assistant model of the rest package:
+// Switch items become switch (or light, if tagged as such), Dimmer and Color items become light,
+// Rollershutter items become cover, Player items become media_player and Number items
+// tagged Setpoint become climate, everything else is a read-only sensor
+package openhab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+type OpenHAB struct {
+	APIURL    string
+	Token     string
+	Fuzz      bool
+	States    []rest.HassState
+	Services  []rest.HassService
+	DeviceMap map[string]string
+	// Protected entities are only sent commands if Confirm returns true
+	Protected []string
+	Confirm   rest.ConfirmFunc
+	Client    *http.Client
+	Retry     rest.Retry
+
+	Result rest.HassResult
+}
+
+// Item is an openHAB item as returned by /items
+type Item struct {
+	Name      string   `json:"name"`
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	GroupType string   `json:"groupType"`
+	State     string   `json:"state"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+}
+
+func New(apiURL string, token string, fuzz bool, deviceMap map[string]string) *OpenHAB {
+	return &OpenHAB{APIURL: apiURL, Token: token, Fuzz: fuzz, DeviceMap: deviceMap}
+}
+
+// client returns the configured http client, or the default one
+func (h *OpenHAB) client() *http.Client {
+	if h.Client != nil {
+		return h.Client
+	}
+	return http.DefaultClient
+}
+
+// openHAB allows anonymous access by default, so only the URL is required
+func (h *OpenHAB) preflight() error {
+	if h.APIURL == "" {
+		return errors.New("no Hub URL found: Run `hctl init` or manually create config")
+	}
+	return nil
+}
+
+// api sends a request to the openHAB REST API, body is sent as text/plain (item commands)
+// Commands are only retried when idempotent is set
+func (h *OpenHAB) api(meth string, path string, body string, idempotent bool) ([]byte, error) {
+	if err := h.preflight(); err != nil {
+		return nil, err
+	}
+
+	retry := h.Retry
+	if !idempotent {
+		retry.Retries = 0
+	}
+
+	rData, status, err := retry.Do(path, func() ([]byte, int, error) {
+		return h.do(meth, path, body)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rData, checkStatus(status, path)
+}
+
+func (h *OpenHAB) do(meth string, path string, body string) ([]byte, int, error) {
+	var r io.Reader
+	if body != "" {
+		r = bytes.NewBufferString(body)
+	}
+	req, err := http.NewRequest(meth, fmt.Sprintf("%s%s", h.APIURL, path), r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	log.Info().Msgf("Requesting URL %s, Method %s, Body: %q", req.URL, req.Method, body)
+	if h.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", h.Token))
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != "" {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+
+	res, err := h.client().Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	rData, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rData, res.StatusCode, nil
+}
+
+func checkStatus(status int, path string) error {
+	switch status {
+	case http.StatusOK, http.StatusAccepted:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("authentication failed: invalid or missing API token")
+	case http.StatusNotFound:
+		return fmt.Errorf("API endpoint not found (404): %s", path)
+	case http.StatusBadRequest:
+		return fmt.Errorf("command rejected by openHAB (400): %s", path)
+	}
+	return fmt.Errorf("unexpected API response code %d for %s", status, path)
+}
+
+// GetItems returns all items
+func (h *OpenHAB) GetItems() ([]Item, error) {
+	res, err := h.api("GET", "/items?recursive=false", "", true)
+	if err != nil {
+		return nil, err
+	}
+	items := []Item{}
+	if err := json.Unmarshal(res, &items); err != nil {
+		log.Debug().Caller().Msgf("Could not create Items Object: %+v", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetItem gets a single item directly from openHAB
+func (h *OpenHAB) GetItem(name string) (Item, error) {
+	var item Item
+	res, err := h.api("GET", fmt.Sprintf("/items/%s", url.PathEscape(name)), "", true)
+	if err != nil {
+		return item, err
+	}
+	if err := json.Unmarshal(res, &item); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// SendCommand sends command (e.g. ON, OFF, 50, UP) to the item
+// As with Home Assistant services, commands are considered idempotent
+func (h *OpenHAB) SendCommand(name string, command string) error {
+	_, err := h.api("POST", fmt.Sprintf("/items/%s", url.PathEscape(name)), command, true)
+	return err
+}
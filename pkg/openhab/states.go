@@ -0,0 +1,312 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openhab
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// services offered per domain, mapped onto item commands in actions.go
+var domainServices = map[string]map[string]rest.HassDomainService{
+	"light": {
+		"turn_on":  {Name: "Turn on", Description: "Sends ON or a brightness/color to the item."},
+		"turn_off": {Name: "Turn off", Description: "Sends OFF to the item."},
+		"toggle":   {Name: "Toggle", Description: "Sends ON or OFF, depending on the current state."},
+	},
+	"switch": {
+		"turn_on":  {Name: "Turn on", Description: "Sends ON to the item."},
+		"turn_off": {Name: "Turn off", Description: "Sends OFF to the item."},
+		"toggle":   {Name: "Toggle", Description: "Sends ON or OFF, depending on the current state."},
+	},
+	"cover": {
+		"open_cover":         {Name: "Open", Description: "Sends UP to the item."},
+		"close_cover":        {Name: "Close", Description: "Sends DOWN to the item."},
+		"stop_cover":         {Name: "Stop", Description: "Sends STOP to the item."},
+		"set_cover_position": {Name: "Set position", Description: "Sends a position to the item."},
+	},
+	"media_player": {
+		"media_play":  {Name: "Play", Description: "Sends PLAY to the item."},
+		"media_pause": {Name: "Pause", Description: "Sends PAUSE to the item."},
+	},
+	"climate": {
+		"set_temperature": {Name: "Set target temperature", Description: "Sends the temperature to the setpoint item."},
+	},
+}
+
+func (i Item) isLight() bool {
+	return slices.Contains(i.Tags, "Lighting") || slices.Contains(i.Tags, "Light") ||
+		slices.Contains([]string{"light", "lightbulb"}, strings.ToLower(i.Category))
+}
+
+// baseType returns the item type without dimension (Number:Temperature -> Number),
+// for groups the type of the group is used
+func (i Item) baseType() string {
+	t := i.Type
+	if t == "Group" {
+		t = i.GroupType
+	}
+	t, _, _ = strings.Cut(t, ":")
+	return t
+}
+
+// Domain returns the Home Assistant domain the item is mapped to
+func (i Item) Domain() string {
+	switch i.baseType() {
+	case "Switch":
+		if i.isLight() {
+			return "light"
+		}
+		return "switch"
+	case "Dimmer", "Color":
+		return "light"
+	case "Rollershutter":
+		return "cover"
+	case "Player":
+		return "media_player"
+	case "Number":
+		if slices.Contains(i.Tags, "Setpoint") {
+			return "climate"
+		}
+	case "":
+		if i.Type == "Group" {
+			return "group"
+		}
+	}
+	return "sensor"
+}
+
+// EntityID returns domain.name, the name is kept as is, as it is needed to send commands
+func (i Item) EntityID() string {
+	return fmt.Sprintf("%s.%s", i.Domain(), i.Name)
+}
+
+// percent returns the brightness of Dimmer (50) and Color (h,s,50) items
+func (i Item) percent() (float64, bool) {
+	s := i.State
+	if i.baseType() == "Color" {
+		parts := strings.Split(s, ",")
+		if len(parts) != 3 {
+			return 0, false
+		}
+		s = parts[2]
+	}
+	p, err := strconv.ParseFloat(s, 64)
+	return p, err == nil
+}
+
+// toBrightness converts 0-100 percent to the 0-255 brightness attribute of Home Assistant
+func toBrightness(p float64) float64 {
+	return math.Round(p / 100 * 255)
+}
+
+// number returns the value of Number items, which may carry a unit (e.g. 21.5 °C)
+func (i Item) number() (float64, bool) {
+	s, _, _ := strings.Cut(i.State, " ")
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+var stateNames = map[string]string{
+	"ON":     "on",
+	"OFF":    "off",
+	"OPEN":   "open",
+	"CLOSED": "closed",
+	"PLAY":   "playing",
+	"PAUSE":  "paused",
+}
+
+// ToState converts the item to a Home Assistant state
+func (i Item) ToState() rest.HassState {
+	name := i.Label
+	if name == "" {
+		name = i.Name
+	}
+	s := rest.HassState{
+		EntityID:   i.EntityID(),
+		State:      i.State,
+		Attributes: map[string]any{"friendly_name": name},
+	}
+	if i.State == "NULL" || i.State == "UNDEF" {
+		s.State = "unavailable"
+		return s
+	}
+	if n, ok := stateNames[i.State]; ok {
+		s.State = n
+	}
+
+	switch i.baseType() {
+	case "Dimmer", "Color":
+		if p, ok := i.percent(); ok {
+			s.State = "off"
+			s.Attributes["brightness"] = nil
+			if p > 0 {
+				s.State = "on"
+				s.Attributes["brightness"] = toBrightness(p)
+			}
+		}
+	case "Rollershutter":
+		// openHAB counts from 0 (up/open) to 100 (down/closed), Home Assistant the other way round
+		if p, err := strconv.ParseFloat(i.State, 64); err == nil {
+			s.State = "open"
+			if p >= 100 {
+				s.State = "closed"
+			}
+			s.Attributes["current_position"] = 100 - p
+		}
+	case "Number":
+		if n, ok := i.number(); ok && i.Domain() == "climate" {
+			s.Attributes["temperature"] = n
+		}
+	}
+	return s
+}
+
+// GetStates returns all items as states
+func (h *OpenHAB) GetStates() ([]rest.HassState, error) {
+	if h.States != nil {
+		log.Info().Msg("Using cached states.")
+		return h.States, nil
+	}
+
+	items, err := h.GetItems()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]rest.HassState, 0, len(items))
+	for _, i := range items {
+		states = append(states, i.ToState())
+	}
+	h.States = states
+	return states, nil
+}
+
+// FetchState gets a single state directly from openHAB, bypassing the cached states
+func (h *OpenHAB) FetchState(_, name string) (rest.HassState, error) {
+	i, err := h.GetItem(name)
+	if err != nil {
+		return rest.HassState{}, err
+	}
+	return i.ToState(), nil
+}
+
+func (h *OpenHAB) GetFilteredStates(domains []string) ([]rest.HassState, error) {
+	states, err := h.GetStates()
+	if err != nil {
+		return nil, err
+	}
+	return rest.FilterDomainsFromStates(states, domains), nil
+}
+
+func (h *OpenHAB) GetFilteredStatesMap(domains []string) (map[string][]string, error) {
+	states, err := h.GetFilteredStates(domains)
+	if err != nil {
+		return nil, err
+	}
+	t := make(map[string][]string)
+	for _, s := range states {
+		d, n, _ := strings.Cut(s.EntityID, ".")
+		t[d] = append(t[d], n)
+	}
+	return t, nil
+}
+
+// GetServices returns the services of all domains openHAB items are mapped to
+func (h *OpenHAB) GetServices() ([]rest.HassService, error) {
+	if h.Services != nil {
+		return h.Services, nil
+	}
+
+	var services []rest.HassService
+	for _, d := range slices.Sorted(maps.Keys(domainServices)) {
+		// copy, as filtering deletes from the services map
+		services = append(services, rest.HassService{Domain: d, Services: maps.Clone(domainServices[d])})
+	}
+	h.Services = services
+	return services, nil
+}
+
+func (h *OpenHAB) GetFilteredServices(domains []string, services []string) ([]rest.HassService, error) {
+	s, err := h.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	s = rest.FilterDomainsFromServices(s, domains)
+	s = rest.FilterServicesFromServices(s, services)
+	return s, nil
+}
+
+func (h *OpenHAB) GetFilteredServicesMap(domains []string, services []string) (map[string][]string, error) {
+	t := make(map[string][]string)
+	s, err := h.GetFilteredServices(domains, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range s {
+		for svc := range d.Services {
+			t[d.Domain] = append(t[d.Domain], svc)
+		}
+	}
+	return t, nil
+}
+
+// UseCache makes h serve states and services from the completion cache
+func (h *OpenHAB) UseCache(states []rest.HassState, services []rest.HassService) {
+	h.States = states
+	h.Services = services
+}
+
+// resolver returns a Home Assistant client working on the item states only,
+// so names are resolved the same way (device_map, fuzzy matching) for all backends
+func (h *OpenHAB) resolver() (*rest.Hass, error) {
+	states, err := h.GetStates()
+	if err != nil {
+		return nil, err
+	}
+	services, err := h.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	r := rest.New("", "", h.Fuzz, h.DeviceMap)
+	r.Protected = h.Protected
+	r.UseCache(states, services)
+	return r, nil
+}
+
+// ResolveEntity resolves a (short, mapped or fuzzy) name to an existing item
+// Returns domain, name
+func (h *OpenHAB) ResolveEntity(name string) (string, string, error) {
+	r, err := h.resolver()
+	if err != nil {
+		return "", "", err
+	}
+	return r.ResolveEntity(name)
+}
+
+func (h *OpenHAB) resolve(args []string, service string) (string, string, error) {
+	r, err := h.resolver()
+	if err != nil {
+		return "", "", err
+	}
+	return r.ResolveEntityForService(args, service)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openhab
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_Item_ToState(t *testing.T) {
+	tests := map[string]struct {
+		item      Item
+		entityID  string
+		state     string
+		attribute string
+		want      any
+	}{
+		"switch": {
+			item:     Item{Name: "Pump", Type: "Switch", State: "ON"},
+			entityID: "switch.Pump",
+			state:    "on",
+		},
+		"switch tagged as light": {
+			item:     Item{Name: "Lamp", Type: "Switch", State: "OFF", Tags: []string{"Lighting"}},
+			entityID: "light.Lamp",
+			state:    "off",
+		},
+		"dimmer on": {
+			item:      Item{Name: "Dimmer", Type: "Dimmer", State: "50"},
+			entityID:  "light.Dimmer",
+			state:     "on",
+			attribute: "brightness",
+			want:      float64(128),
+		},
+		"dimmer off": {
+			item:      Item{Name: "Dimmer", Type: "Dimmer", State: "0"},
+			entityID:  "light.Dimmer",
+			state:     "off",
+			attribute: "brightness",
+			want:      nil,
+		},
+		"color": {
+			item:      Item{Name: "Color", Type: "Color", State: "120,100,100"},
+			entityID:  "light.Color",
+			state:     "on",
+			attribute: "brightness",
+			want:      float64(255),
+		},
+		"rollershutter": {
+			item:      Item{Name: "Shutter", Type: "Rollershutter", State: "30"},
+			entityID:  "cover.Shutter",
+			state:     "open",
+			attribute: "current_position",
+			want:      float64(70),
+		},
+		"setpoint": {
+			item:      Item{Name: "Setpoint", Type: "Number:Temperature", State: "21.5 °C", Tags: []string{"Setpoint"}},
+			entityID:  "climate.Setpoint",
+			state:     "21.5 °C",
+			attribute: "temperature",
+			want:      21.5,
+		},
+		"contact": {
+			item:     Item{Name: "Door", Type: "Contact", State: "CLOSED"},
+			entityID: "sensor.Door",
+			state:    "closed",
+		},
+		"switch group": {
+			item:     Item{Name: "Lights", Type: "Group", GroupType: "Switch", State: "ON"},
+			entityID: "switch.Lights",
+			state:    "on",
+		},
+		"undefined": {
+			item:     Item{Name: "Pump", Type: "Switch", State: "NULL"},
+			entityID: "switch.Pump",
+			state:    "unavailable",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := tt.item.ToState()
+			if s.EntityID != tt.entityID {
+				t.Errorf("got entity id %s, want %s", s.EntityID, tt.entityID)
+			}
+			if s.State != tt.state {
+				t.Errorf("got state %s, want %s", s.State, tt.state)
+			}
+			if tt.attribute != "" && !reflect.DeepEqual(s.Attributes[tt.attribute], tt.want) {
+				t.Errorf("got %s %v, want %v", tt.attribute, s.Attributes[tt.attribute], tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetFilteredStatesMap(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	defer ms.Close()
+	h := New(ms.URL, "", false, nil)
+
+	got, err := h.GetFilteredStatesMap([]string{"light", "cover"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"light": {"Livingroom_Light", "Kitchen_Dimmer", "Bedroom_Color"},
+		"cover": {"Office_Shutter"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func Test_ResolveEntity(t *testing.T) {
+	ms := hctltest.OpenHABMockServer(t)
+	defer ms.Close()
+	h := New(ms.URL, "", true, map[string]string{"pump": "switch.Garden_Pump"})
+
+	tests := map[string]struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		"exact":      {name: "Kitchen_Dimmer", want: "light.Kitchen_Dimmer"},
+		"device map": {name: "pump", want: "switch.Garden_Pump"},
+		"fuzzy":      {name: "Office", want: "cover.Office_Shutter"},
+		"not found":  {name: "Garage_Door", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, n, err := h.ResolveEntity(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s.%s", d, n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d + "." + n; got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Formats lists the supported values of --output, besides the default pterm output
+var Formats = []string{"json", "yaml", "tsv", "csv", "go-template=TEMPLATE"}
+
+// format is set once per run with SetFormat, empty for pterm output
+var (
+	format string
+	tmpl   *template.Template
+)
+
+// Writer remembers the columns of the last tsv/csv record written to it, so the
+// header is only repeated when the columns change (e.g. an error after a table row)
+type Writer struct {
+	io.Writer
+	header []string
+}
+
+// NewWriter returns a Writer for out, use one per output stream
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{Writer: out}
+}
+
+// Record is the outcome of an action or an entity, as printed with --output
+type Record struct {
+	EntityID   string         `json:"entity_id,omitempty" yaml:"entity_id,omitempty"`
+	State      string         `json:"state,omitempty" yaml:"state,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+	Action     string         `json:"action,omitempty" yaml:"action,omitempty"`
+	Result     string         `json:"result,omitempty" yaml:"result,omitempty"`
+	Message    string         `json:"message,omitempty" yaml:"message,omitempty"`
+	Error      string         `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Row is a record with ordered columns, e.g. a row of a table
+type Row struct {
+	Keys   []string
+	Values []any
+}
+
+// NewRow returns a row of a table with header as keys (lower case, spaces replaced by _)
+func NewRow(header []any, values []any) Row {
+	r := Row{Values: values}
+	for _, h := range header {
+		r.Keys = append(r.Keys, strings.ReplaceAll(strings.ToLower(fmt.Sprint(h)), " ", "_"))
+	}
+	return r
+}
+
+// MarshalJSON keeps the order of the columns
+func (r Row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		if i < len(r.Values) {
+			v = r.Values[i]
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Results of a Record
+const (
+	ResultSuccess   = "success"
+	ResultUnchanged = "unchanged"
+	ResultError     = "error"
+)
+
+// SetFormat sets the output format for all following prints
+// Supported are json (one object per line), yaml (one document per record), tsv and csv
+// (with header) and go-template=TEMPLATE (executed per record), empty resets to pterm output
+func SetFormat(f string) error {
+	format, tmpl = "", nil
+	switch {
+	case f == "", f == "json", f == "yaml", f == "tsv", f == "csv":
+		format = f
+	case strings.HasPrefix(f, "go-template="):
+		t, err := template.New("output").Option("missingkey=zero").Parse(strings.TrimPrefix(f, "go-template="))
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %v", err)
+		}
+		format, tmpl = "go-template", t
+	case f == "go-template":
+		return fmt.Errorf("go-template needs a template, e.g. -o go-template='{{.entity_id}} {{.state}}'")
+	default:
+		return fmt.Errorf("unknown output format: %s (Supported: %s)", f, strings.Join(Formats, ", "))
+	}
+	return nil
+}
+
+// Structured reports whether --output asks for machine-readable records
+func Structured() bool {
+	return format != ""
+}
+
+// toMap converts v to the generic form all formats work on, keys as in JSON
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// columns returns the json names of all fields of struct v in order,
+// or the sorted keys of map v
+func columnsOf(v any, m map[string]any) []string {
+	if r, ok := v.(Row); ok {
+		return r.Keys
+	}
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return slices.Sorted(maps.Keys(m))
+	}
+	var cols []string
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		cols = append(cols, name)
+	}
+	return cols
+}
+
+// cellValue prints strings as they are and everything else as JSON
+func cellValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+// writeCells writes rows as tsv, tabs and newlines are escaped to keep one record per line,
+// or as csv
+func writeCells(out io.Writer, rows [][]string) error {
+	if format == "csv" {
+		w := csv.NewWriter(out)
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	r := strings.NewReplacer("\t", `\t`, "\n", `\n`)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = r.Replace(c)
+		}
+		if _, err := fmt.Fprintln(out, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FprintRecord prints v (a Record or any other struct/map) in the format set with SetFormat
+func FprintRecord(out io.Writer, v any) error {
+	switch format {
+	case "json":
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	case "yaml":
+		m, err := toMap(v)
+		if err != nil {
+			return err
+		}
+		// keep the order of the columns, nested maps are sorted by yaml
+		doc := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range columnsOf(v, m) {
+			val, ok := m[k]
+			if !ok {
+				continue
+			}
+			var n yaml.Node
+			if err := n.Encode(val); err != nil {
+				return err
+			}
+			doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, &n)
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "---\n%s", buf.String())
+		return err
+	case "tsv", "csv":
+		m, err := toMap(v)
+		if err != nil {
+			return err
+		}
+		// without a Writer to remember the columns, every record gets a header
+		var cells [][]string
+		header := columnsOf(v, m)
+		if w, ok := out.(*Writer); !ok || !slices.Equal(w.header, header) {
+			cells = append(cells, header)
+			if ok {
+				w.header = header
+			}
+		}
+		values := make([]string, len(header))
+		for i, c := range header {
+			values[i] = cellValue(m[c])
+		}
+		cells = append(cells, values)
+		return writeCells(out, cells)
+	case "go-template":
+		m, err := toMap(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, m); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, buf.String())
+		return err
+	}
+	return fmt.Errorf("no output format set")
+}
+
+// fprintRecord is FprintRecord for the pterm helpers, which can't return errors either
+func fprintRecord(out io.Writer, v any) {
+	if err := FprintRecord(out, v); err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_FprintRecordHeader(t *testing.T) {
+	t.Cleanup(func() { _ = SetFormat("") })
+
+	tests := map[string]struct {
+		format string
+		want   string
+	}{
+		"csv": {
+			format: "csv",
+			want: "name,state\nkitchen,on\nhall,off\n" +
+				"entity_id,state,attributes,action,result,message,error\nlight.attic,,,on,error,,not found\n" +
+				"name,state\ncellar,on\n",
+		},
+		"tsv": {
+			format: "tsv",
+			want: "name\tstate\nkitchen\ton\nhall\toff\n" +
+				"entity_id\tstate\tattributes\taction\tresult\tmessage\terror\nlight.attic\t\t\ton\terror\t\tnot found\n" +
+				"name\tstate\ncellar\ton\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := SetFormat(tt.format); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			for _, v := range []any{
+				Row{Keys: []string{"name", "state"}, Values: []any{"kitchen", "on"}},
+				Row{Keys: []string{"name", "state"}, Values: []any{"hall", "off"}},
+				Record{EntityID: "light.attic", Action: "on", Result: ResultError, Error: "not found"},
+				Row{Keys: []string{"name", "state"}, Values: []any{"cellar", "on"}},
+			} {
+				if err := FprintRecord(w, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,10 +19,13 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/gosuri/uitable"
 	"github.com/pterm/pterm"
 	"github.com/pterm/pterm/putils"
+
+	"github.com/xx4h/hctl/pkg/rest"
 )
 
 func GetBanner() (string, error) {
@@ -32,6 +35,10 @@ func GetBanner() (string, error) {
 }
 
 func FprintSuccess(out io.Writer, str string) {
+	if Structured() {
+		fprintRecord(out, Record{Result: ResultSuccess, Message: str})
+		return
+	}
 	pterm.Fprint(out, pterm.Success.Sprintln(str))
 }
 
@@ -40,6 +47,10 @@ func PrintSuccess(str string) {
 }
 
 func FprintSuccessAction(out io.Writer, obj string, state string) {
+	if Structured() {
+		fprintRecord(out, Record{EntityID: obj, State: state, Result: ResultSuccess})
+		return
+	}
 	pterm.Fprint(out, pterm.Success.Sprintfln("%s %s", obj, state))
 }
 
@@ -47,6 +58,54 @@ func PrintSuccessAction(obj string, state string) {
 	pterm.Success.Printfln("%s %s", obj, state)
 }
 
+func FprintWarning(out io.Writer, str string) {
+	if Structured() {
+		fprintRecord(out, Record{Message: str})
+		return
+	}
+	pterm.Fprint(out, pterm.Warning.Sprintln(str))
+}
+
+// FprintResult prints the outcome of an action on entityID (domain.name) as reported by the hub
+// If action is empty, the new state reported by the hub is printed instead
+func FprintResult(out io.Writer, entityID string, action string, results []rest.HassResult) {
+	r, ok := rest.FindResult(results, entityID)
+	if Structured() {
+		rec := Record{EntityID: r.EntityID, State: r.State, Action: action, Result: ResultSuccess}
+		if !ok {
+			rec.EntityID, rec.Result, rec.Message = entityID, ResultUnchanged, "hub reported no state change"
+		}
+		fprintRecord(out, rec)
+		return
+	}
+	// humans get the short name, records always the full entity id
+	_, name, _ := strings.Cut(entityID, ".")
+	if !ok {
+		if action == "" {
+			FprintWarning(out, fmt.Sprintf("%s: hub reported no state change", name))
+		} else {
+			FprintWarning(out, fmt.Sprintf("%s %s: hub reported no state change", name, action))
+		}
+		return
+	}
+	if action == "" {
+		action = r.State
+	}
+	FprintSuccessAction(out, r.Name(name), action)
+}
+
+// FprintResults prints all states reported by the hub after action
+func FprintResults(out io.Writer, action string, results []rest.HassResult) {
+	for _, r := range results {
+		if Structured() {
+			fprintRecord(out, Record{EntityID: r.EntityID, State: r.State, Action: action, Result: ResultSuccess})
+			continue
+		}
+		_, name, _ := strings.Cut(r.EntityID, ".")
+		FprintSuccessAction(out, r.Name(name), r.State)
+	}
+}
+
 func ListWithHeader(header []interface{}, list [][]interface{}) *uitable.Table {
 	table := uitable.New()
 	table.AddRow(header...)
@@ -57,6 +116,12 @@ func ListWithHeader(header []interface{}, list [][]interface{}) *uitable.Table {
 }
 
 func FprintSuccessListWithHeader(out io.Writer, header []interface{}, list [][]interface{}) {
+	if Structured() {
+		for _, entry := range list {
+			fprintRecord(out, NewRow(header, entry))
+		}
+		return
+	}
 	fmt.Fprintln(out, ListWithHeader(header, list))
 }
 
@@ -65,6 +130,10 @@ func PrintSuccessListWithHeader(header []interface{}, list [][]interface{}) {
 }
 
 func FprintErrorMsg(out io.Writer, err error) {
+	if Structured() {
+		fprintRecord(out, Record{Result: ResultError, Error: err.Error()})
+		return
+	}
 	pterm.Fprint(out, pterm.Error.Sprintln(err))
 }
 
@@ -0,0 +1,56 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// SetConfirm sets how service calls for protected_entities (top-level or of the context in use) are confirmed
+// Without it, protected entities can't be acted on
+func (h *Hctl) SetConfirm(confirm rest.ConfirmFunc) {
+	h.confirm = confirm
+}
+
+// ResolveInDomains resolves name (short, mapped or fuzzy) to an entity of one of domains
+// Security devices and protected entities are never matched fuzzy
+// Returns the entity id
+func (h *Hctl) ResolveInDomains(name string, domains ...string) (string, error) {
+	c, err := h.GetHass(strings.Join(domains, "/"))
+	if err != nil {
+		return "", err
+	}
+	d, obj, err := c.ResolveEntityInDomains(name, domains...)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", d, obj), nil
+}
+
+// CallEntity calls service of the domain of entityID for entityID with data (may be empty)
+func (h *Hctl) CallEntity(entityID string, service string, data map[string]any) ([]rest.HassResult, error) {
+	domain, _, ok := strings.Cut(entityID, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid entity id: %s", entityID)
+	}
+	c, err := h.GetHass(domain)
+	if err != nil {
+		return nil, err
+	}
+	return c.CallService(domain, service, []string{entityID}, data)
+}
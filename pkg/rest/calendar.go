@@ -0,0 +1,115 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// HassCalendar is a calendar entity as listed by /calendars
+type HassCalendar struct {
+	EntityID string `json:"entity_id"`
+	Name     string `json:"name"`
+}
+
+// CalendarTime is either a date (all-day events) or a date with time
+type CalendarTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+}
+
+// CalendarEvent is an event of a calendar, the end of all-day events is exclusive
+type CalendarEvent struct {
+	Calendar     string       `json:"calendar"`
+	Summary      string       `json:"summary"`
+	Start        CalendarTime `json:"start"`
+	End          CalendarTime `json:"end"`
+	Description  string       `json:"description,omitempty"`
+	Location     string       `json:"location,omitempty"`
+	UID          string       `json:"uid,omitempty"`
+	RecurrenceID string       `json:"recurrence_id,omitempty"`
+	RRule        string       `json:"rrule,omitempty"`
+}
+
+// AllDay returns true if the event has dates only
+func (e CalendarEvent) AllDay() bool {
+	return e.Start.DateTime == ""
+}
+
+// Time returns the point in time, dates are midnight in local time
+func (t CalendarTime) Time() (time.Time, error) {
+	if t.DateTime != "" {
+		return time.Parse(time.RFC3339, t.DateTime)
+	}
+	return time.ParseInLocation(time.DateOnly, t.Date, time.Local)
+}
+
+// GetCalendars returns all calendar entities
+func (h *Hass) GetCalendars() ([]HassCalendar, error) {
+	res, err := h.api("GET", "/calendars", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := []HassCalendar{}
+	if err := json.Unmarshal(res, &calendars); err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+// GetCalendarEvents returns the events of a calendar (resolved like in any other action)
+// between start and end, Calendar of each event is set to the entity id
+func (h *Hass) GetCalendarEvents(calendar string, start time.Time, end time.Time) ([]CalendarEvent, error) {
+	domain, obj, err := h.ResolveEntityInDomains(calendar, "calendar")
+	if err != nil {
+		return nil, err
+	}
+	entityID := fmt.Sprintf("%s.%s", domain, obj)
+
+	q := url.Values{}
+	q.Set("start", start.Format(time.RFC3339))
+	q.Set("end", end.Format(time.RFC3339))
+	res, err := h.api("GET", fmt.Sprintf("/calendars/%s?%s", entityID, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []CalendarEvent{}
+	if err := json.Unmarshal(res, &events); err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].Calendar = entityID
+	}
+	return events, nil
+}
+
+// CreateCalendarEvent adds an event to a calendar via calendar.create_event and
+// returns the entity id of the calendar
+func (h *Hass) CreateCalendarEvent(calendar string, data map[string]any) (string, error) {
+	domain, obj, err := h.ResolveEntityInDomains(calendar, "calendar")
+	if err != nil {
+		return "", err
+	}
+	entityID := fmt.Sprintf("%s.%s", domain, obj)
+	if _, err := h.CallService("calendar", "create_event", []string{entityID}, data); err != nil {
+		return "", err
+	}
+	return entityID, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_GetCalendars(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	h := &Hass{APIURL: ms.URL, Token: "test_token"}
+	calendars, err := h.GetCalendars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calendars) != 2 {
+		t.Fatalf("got %d calendars, want 2", len(calendars))
+	}
+	if calendars[0].EntityID != "calendar.household" || calendars[0].Name != "Household" {
+		t.Errorf("got %+v, want calendar.household named Household", calendars[0])
+	}
+}
+
+func Test_GetCalendarEvents(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	start := time.Date(2024, 10, 9, 0, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		calendar string
+		end      time.Time
+		want     []string
+		wantErr  string
+	}{
+		"week": {
+			calendar: "household",
+			end:      start.AddDate(0, 0, 7),
+			want:     []string{"Bin collection: paper", "Dentist", "Vacation"},
+		},
+		"entity id": {
+			calendar: "calendar.holidays",
+			end:      start.AddDate(0, 1, 0),
+			want:     []string{"Halloween"},
+		},
+		"empty period": {
+			calendar: "holidays",
+			end:      start.AddDate(0, 0, 1),
+			want:     []string{},
+		},
+		"not a calendar": {
+			calendar: "light.livingroom_main",
+			end:      start.AddDate(0, 0, 7),
+			wantErr:  "calendar",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			events, err := h.GetCalendarEvents(tt.calendar, start, tt.end)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, e := range events {
+				if !strings.HasPrefix(e.Calendar, "calendar.") {
+					t.Errorf("calendar of %s not set: %q", e.Summary, e.Calendar)
+				}
+				got = append(got, e.Summary)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CalendarTime(t *testing.T) {
+	e := CalendarEvent{Start: CalendarTime{Date: "2024-10-10"}, End: CalendarTime{Date: "2024-10-11"}}
+	if !e.AllDay() {
+		t.Error("event with dates only should be all-day")
+	}
+	got, err := e.Start.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 10, 10, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	dt := CalendarTime{DateTime: "2024-10-11T09:30:00+02:00"}
+	got, err = dt.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 10, 11, 7, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func Test_CreateCalendarEvent(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	h := &Hass{APIURL: ms.URL, Token: "test_token"}
+	got, err := h.CreateCalendarEvent("household", map[string]any{"summary": "Glass", "start_date": "2024-10-24", "end_date": "2024-10-25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "calendar.household" {
+		t.Errorf("got %s, want calendar.household", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"maps"
+)
+
+// CallService calls domain.service with data, targeting the given entities (resolved
+// like in any other action, may be empty)
+// Service calls are not retried, as hctl can't know if they are idempotent
+// Returns the states changed by the call
+func (h *Hass) CallService(domain string, service string, entities []string, data map[string]any) ([]HassResult, error) {
+	exists, err := h.domainExists(domain)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("domain %s does not exist", domain)
+	}
+	hasSvc, err := h.domainHasService(domain, service)
+	if err != nil {
+		return nil, err
+	}
+	if !hasSvc {
+		return nil, fmt.Errorf("domain %s has no service %s", domain, service)
+	}
+
+	payload := maps.Clone(data)
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	if len(entities) > 0 {
+		// entities of e.g. light.turn_on have to be lights, while e.g.
+		// homeassistant.turn_on targets entities of any domain
+		entityDomain, err := h.domainHasEntities(domain)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, e := range entities {
+			var d, n string
+			if ed, _ := splitDomainAndName(e); entityDomain && ed != "" {
+				d, n, err = h.ResolveEntity(e)
+				if err == nil && d != domain {
+					err = fmt.Errorf("%s.%s is not an entity of domain %s", d, n, domain)
+				}
+			} else if entityDomain {
+				d, n, err = h.ResolveEntityInDomains(e, domain)
+			} else {
+				d, n, err = h.entityArgHandler([]string{e}, service)
+			}
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, fmt.Sprintf("%s.%s", d, n))
+		}
+		payload["entity_id"] = ids
+	}
+
+	res, err := h.callService(domain, service, payload, false)
+	if err != nil {
+		return nil, err
+	}
+	return h.getResult(res)
+}
+
+// domainHasEntities returns true if there are entities of domain, e.g. light
+func (h *Hass) domainHasEntities(domain string) (bool, error) {
+	states, err := h.GetStates()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range states {
+		if d, _ := splitDomainAndName(s.EntityID); d == domain {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_CallService(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		domain   string
+		service  string
+		entities []string
+		data     map[string]any
+		want     []string
+		wantErr  string
+	}{
+		"unknown domain": {
+			domain:  "nodomain",
+			service: "turn_on",
+			wantErr: "domain nodomain does not exist",
+		},
+		"unknown service": {
+			domain:  "light",
+			service: "speak",
+			wantErr: "domain light has no service speak",
+		},
+		"unknown entity": {
+			domain:   "light",
+			service:  "turn_on",
+			entities: []string{"light.nonexisting"},
+			wantErr:  "entity light.nonexisting does not exist",
+		},
+		"name of another domain": {
+			domain:   "light",
+			service:  "turn_on",
+			entities: []string{"livingroom_warp"},
+			wantErr:  "no light entity found for livingroom_warp",
+		},
+		"fuzzy name of another domain": {
+			domain:   "light",
+			service:  "turn_on",
+			entities: []string{"bedroom_wrp"},
+			wantErr:  "no light entity found for bedroom_wrp",
+		},
+		"entity of another domain": {
+			domain:   "light",
+			service:  "turn_on",
+			entities: []string{"switch.livingroom_warp"},
+			wantErr:  "switch.livingroom_warp is not an entity of domain light",
+		},
+		"any domain": {
+			domain:   "homeassistant",
+			service:  "turn_on",
+			entities: []string{"livingroom_warp"},
+			want:     []string{"switch.livingroom_warp"},
+		},
+		"multiple entities with data": {
+			domain:   "light",
+			service:  "turn_on",
+			entities: []string{"livingroom_main", "light.livingroom_other"},
+			data:     map[string]any{"brightness": 100},
+			want:     []string{"light.livingroom_main", "light.livingroom_other"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token", Fuzz: true}
+			results, err := h.CallService(tt.domain, tt.service, tt.entities, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range tt.want {
+				if _, ok := FindResult(results, id); !ok {
+					t.Errorf("no result for %s in %v", id, results)
+				}
+			}
+		})
+	}
+}
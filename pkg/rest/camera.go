@@ -0,0 +1,36 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+)
+
+// ImageDomains are the domains with images, served by /<domain>_proxy/<entity_id>
+var ImageDomains = []string{"camera", "image"}
+
+// GetImage returns the entity id and the current image of a camera (a snapshot) or an image entity
+func (h *Hass) GetImage(name string) (string, []byte, error) {
+	domain, obj, err := h.ResolveEntityInDomains(name, ImageDomains...)
+	if err != nil {
+		return "", nil, err
+	}
+	entityID := fmt.Sprintf("%s.%s", domain, obj)
+	res, err := h.api("GET", fmt.Sprintf("/%s_proxy/%s", domain, entityID), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return entityID, res, nil
+}
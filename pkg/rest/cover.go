@@ -0,0 +1,154 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// cover features as reported in the `supported_features` attribute
+const (
+	CoverOpen            = 1
+	CoverClose           = 2
+	CoverSetPosition     = 4
+	CoverStop            = 8
+	CoverSetTiltPosition = 128
+)
+
+type coverAction struct {
+	service string
+	feature int
+	target  string
+	done    string
+}
+
+var coverActions = map[string]coverAction{
+	"open":  {service: "open_cover", feature: CoverOpen, target: "open", done: "opened"},
+	"close": {service: "close_cover", feature: CoverClose, target: "closed", done: "closed"},
+	"stop":  {service: "stop_cover", feature: CoverStop, done: "stopped"},
+}
+
+// positionStep steps the current position (0-100) of attr up or down to the next multiple of 10
+func positionStep(state HassState, attr string, updown string) (int, error) {
+	curany, ok := state.Attributes[attr]
+	if !ok || curany == nil {
+		return 0, fmt.Errorf("state `%s` has no attribute `%s`", state.EntityID, attr)
+	}
+	cur, ok := toFloat(curany)
+	if !ok {
+		return 0, fmt.Errorf("state `%s` has invalid attribute `%s`: %v", state.EntityID, attr, curany)
+	}
+
+	p := int(math.Round(cur))
+	diff := p % 10
+	switch updown {
+	case "+":
+		return min(p+(10-diff), 100), nil
+	case "-":
+		b := p - diff
+		if diff == 0 {
+			b = p - 10
+		}
+		return max(b, 0), nil
+	default:
+		return 0, fmt.Errorf("no such positionStep: %s", updown)
+	}
+}
+
+// CoverAction opens, closes or stops a cover
+func (h *Hass) CoverAction(obj string, action string) (string, string, string, []HassResult, error) {
+	a, ok := coverActions[action]
+	if !ok {
+		return "", "", "", nil, fmt.Errorf("no such cover action: %s", action)
+	}
+	sub, obj, err := h.ResolveEntityInDomains(obj, "cover")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	state, err := h.GetState(sub, obj)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if err := supportsFeature(state, a.feature, action); err != nil {
+		return "", "", "", nil, err
+	}
+
+	payload := map[string]any{
+		"entity_id": fmt.Sprintf("%s.%s", sub, obj),
+	}
+	res, err := h.callService(sub, a.service, payload, true)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(sub, obj, a.target, nil)
+
+	results, err := h.getResult(res)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	return obj, a.done, sub, results, nil
+}
+
+// CoverPosition sets the position (or tilt position, if tilt is set) of a cover
+// position is either 0-100, or +/- to step by 10
+func (h *Hass) CoverPosition(obj string, position string, tilt bool) (string, string, string, []HassResult, error) {
+	svc, attr, key, feature, name := "set_cover_position", "current_position", "position", CoverSetPosition, "position"
+	if tilt {
+		svc, attr, key, feature, name = "set_cover_tilt_position", "current_tilt_position", "tilt_position", CoverSetTiltPosition, "tilt"
+	}
+	sub, obj, err := h.ResolveEntityInDomains(obj, "cover")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	state, err := h.GetState(sub, obj)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if err := supportsFeature(state, feature, name); err != nil {
+		return "", "", "", nil, err
+	}
+
+	var p int
+	switch position {
+	case "+", "-":
+		p, err = positionStep(state, attr, position)
+		if err != nil {
+			return "", "", "", nil, err
+		}
+	default:
+		p, err = strconv.Atoi(position)
+		if err != nil || p < 0 || p > 100 {
+			return "", "", "", nil, fmt.Errorf("%s needs to be 0-100, or +/-", name)
+		}
+	}
+
+	payload := map[string]any{
+		"entity_id": fmt.Sprintf("%s.%s", sub, obj),
+		key:         p,
+	}
+	res, err := h.callService(sub, svc, payload, true)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(sub, obj, "", map[string]float64{attr: float64(p)})
+
+	results, err := h.getResult(res)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	return obj, fmt.Sprintf("%s set to %d%%", name, p), sub, results, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_positionStep(t *testing.T) {
+	tests := map[string]struct {
+		cur     any
+		updown  string
+		want    int
+		wantErr bool
+	}{
+		"up":            {cur: 62.0, updown: "+", want: 70},
+		"up even":       {cur: 60.0, updown: "+", want: 70},
+		"up max":        {cur: 100.0, updown: "+", want: 100},
+		"down":          {cur: 62.0, updown: "-", want: 60},
+		"down even":     {cur: 60.0, updown: "-", want: 50},
+		"down min":      {cur: 0.0, updown: "-", want: 0},
+		"no position":   {cur: nil, updown: "+", wantErr: true},
+		"invalid step":  {cur: 60.0, updown: "*", wantErr: true},
+		"invalid value": {cur: "half", updown: "+", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := HassState{EntityID: "cover.test", Attributes: map[string]any{"current_position": tt.cur}}
+			got, err := positionStep(state, "current_position", tt.updown)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CoverAction(t *testing.T) {
+	tests := map[string]struct {
+		cover     string
+		action    string
+		wantState string
+		wantErr   bool
+	}{
+		"open":           {cover: "bedroom_blinds", action: "open", wantState: "open"},
+		"close":          {cover: "kitchen_blinds", action: "close", wantState: "closed"},
+		"stop":           {cover: "kitchen_blinds", action: "stop", wantState: "open"},
+		"unsupported":    {cover: "carport_gate", action: "stop", wantErr: true},
+		"no cover":       {cover: "bedroom_main", action: "open", wantErr: true},
+		"invalid action": {cover: "kitchen_blinds", action: "wiggle", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ms := hctltest.MockServer(t)
+			defer ms.Close()
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			obj, _, sub, results, err := h.CoverAction(tt.cover, tt.action)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", results)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sub != "cover" || obj != tt.cover {
+				t.Errorf("got %s.%s, want cover.%s", sub, obj, tt.cover)
+			}
+			if tt.action == "stop" {
+				return
+			}
+			r, ok := FindResult(results, sub+"."+obj)
+			if !ok {
+				t.Fatalf("no result for %s in %+v", obj, results)
+			}
+			if r.State != tt.wantState {
+				t.Errorf("got state %s, want %s", r.State, tt.wantState)
+			}
+		})
+	}
+}
+
+func Test_CoverPosition(t *testing.T) {
+	tests := map[string]struct {
+		cover    string
+		position string
+		tilt     bool
+		want     string
+		wantErr  bool
+	}{
+		"position":         {cover: "kitchen_blinds", position: "30", want: "position set to 30%"},
+		"position up":      {cover: "kitchen_blinds", position: "+", want: "position set to 70%"},
+		"position down":    {cover: "kitchen_blinds", position: "-", want: "position set to 60%"},
+		"tilt":             {cover: "kitchen_blinds", position: "0", tilt: true, want: "tilt set to 0%"},
+		"tilt up":          {cover: "kitchen_blinds", position: "+", tilt: true, want: "tilt set to 60%"},
+		"tilt unsupported": {cover: "bedroom_blinds", position: "50", tilt: true, wantErr: true},
+		"unsupported":      {cover: "carport_gate", position: "50", wantErr: true},
+		"out of range":     {cover: "kitchen_blinds", position: "101", wantErr: true},
+		"invalid":          {cover: "kitchen_blinds", position: "half", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ms := hctltest.MockServer(t)
+			defer ms.Close()
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			_, got, _, _, err := h.CoverPosition(tt.cover, tt.position, tt.tilt)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LogLevels are the levels of the Home Assistant log, from least to most severe
+var LogLevels = []string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"}
+
+// e.g. 2024-10-09 06:12:45.903 ERROR (MainThread) [homeassistant.components.mqtt.client] Failed to connect
+var logLine = regexp.MustCompile(`^(\d{4}-\d\d-\d\d \d\d:\d\d:\d\d(?:\.\d+)?) ([A-Z]+) \(([^)]*)\) \[([^\]]+)\] (.*)$`)
+
+// LogEntry is an entry of the error log, continuation lines (e.g. tracebacks) are part of Message
+type LogEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Thread  string `json:"thread"`
+	Logger  string `json:"logger"`
+	Message string `json:"message"`
+}
+
+// String returns the entry as written to the log
+func (e LogEntry) String() string {
+	if e.Time == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s %s (%s) [%s] %s", e.Time, e.Level, e.Thread, e.Logger, e.Message)
+}
+
+// Severity returns the position of the level in LogLevels, -1 if unknown
+func (e LogEntry) Severity() int {
+	return slices.Index(LogLevels, e.Level)
+}
+
+// GetErrorLog returns the raw log of the current run of the hub
+func (h *Hass) GetErrorLog() (string, error) {
+	res, err := h.api("GET", "/error_log", nil)
+	if err != nil {
+		return "", err
+	}
+	return string(res), nil
+}
+
+// ParseErrorLog splits a log into entries, lines before the first entry become entries without level
+func ParseErrorLog(log string) []LogEntry {
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimRight(log, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		if m := logLine.FindStringSubmatch(line); m != nil {
+			entries = append(entries, LogEntry{Time: m[1], Level: m[2], Thread: m[3], Logger: m[4], Message: m[5]})
+			continue
+		}
+		if len(entries) == 0 {
+			entries = append(entries, LogEntry{Message: line})
+			continue
+		}
+		entries[len(entries)-1].Message += "\n" + line
+	}
+	return entries
+}
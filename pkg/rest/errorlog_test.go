@@ -0,0 +1,66 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_ParseErrorLog(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	h := &Hass{APIURL: ms.URL, Token: "test_token"}
+	log, err := h.GetErrorLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := ParseErrorLog(log)
+	if len(entries) != 7 {
+		t.Fatalf("got %d entries, want 7", len(entries))
+	}
+
+	e := entries[4]
+	if e.Level != "ERROR" || e.Logger != "homeassistant.components.automation.garage_light" || e.Thread != "MainThread" {
+		t.Errorf("got %+v", e)
+	}
+	if !strings.HasSuffix(e.Message, "\nhomeassistant.exceptions.ServiceNotFound: Service light.turn_onn not found") {
+		t.Errorf("traceback not part of the entry: %q", e.Message)
+	}
+	if e.Severity() != 3 || entries[5].Severity() != 0 {
+		t.Errorf("got severities %d and %d", e.Severity(), entries[5].Severity())
+	}
+
+	var lines []string
+	for _, e := range entries {
+		lines = append(lines, e.String())
+	}
+	if got := strings.Join(lines, "\n") + "\n"; got != log {
+		t.Errorf("entries don't print as logged:\n%s", got)
+	}
+}
+
+func Test_ParseErrorLogContinuation(t *testing.T) {
+	entries := ParseErrorLog("  File \"script.py\", line 1\nKeyError: 'x'\n2024-10-09 05:00:02.220 INFO (MainThread) [homeassistant.bootstrap] started\n")
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Level != "" || entries[0].Severity() != -1 || entries[0].String() != "  File \"script.py\", line 1\nKeyError: 'x'" {
+		t.Errorf("got %+v", entries[0])
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// HassEvent is an event type with the number of its listeners
+type HassEvent struct {
+	Event         string `json:"event"`
+	ListenerCount int    `json:"listener_count"`
+}
+
+// GetEvents returns all event types that have listeners, sorted by name
+func (h *Hass) GetEvents() ([]HassEvent, error) {
+	res, err := h.api("GET", "/events", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []HassEvent{}
+	if err := json.Unmarshal(res, &events); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Event < events[j].Event })
+	return events, nil
+}
+
+// FireEvent fires an event of eventType with data as event data and returns the message of the hub
+func (h *Hass) FireEvent(eventType string, data map[string]any) (string, error) {
+	if eventType == "" {
+		return "", fmt.Errorf("event type must not be empty")
+	}
+	res, err := h.api("POST", fmt.Sprintf("/events/%s", url.PathEscape(eventType)), data)
+	if err != nil {
+		return "", err
+	}
+
+	var msg struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(res, &msg); err != nil {
+		return "", err
+	}
+	return msg.Message, nil
+}
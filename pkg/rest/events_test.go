@@ -0,0 +1,82 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_GetEvents(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	h := &Hass{APIURL: ms.URL, Token: "test_token"}
+	events, err := h.GetEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("got %d events, want 5", len(events))
+	}
+	if events[0].Event != "automation_triggered" || events[4].Event != "state_changed" || events[4].ListenerCount != 12 {
+		t.Errorf("events not sorted or incomplete: %+v", events)
+	}
+}
+
+func Test_FireEvent(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		eventType string
+		data      map[string]any
+		want      string
+		wantErr   string
+	}{
+		"without data": {
+			eventType: "deploy_finished",
+			want:      "Event deploy_finished fired.",
+		},
+		"with data": {
+			eventType: "garage_door_reminder",
+			data:      map[string]any{"minutes": 15},
+			want:      "Event garage_door_reminder fired.",
+		},
+		"empty event type": {
+			wantErr: "event type must not be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			got, err := h.FireEvent(tt.eventType, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// fan features as reported in the `supported_features` attribute
+const (
+	FanSetSpeed   = 1
+	FanOscillate  = 2
+	FanDirection  = 4
+	FanPresetMode = 8
+)
+
+// FanDirections are the directions a fan can turn in
+var FanDirections = []string{"forward", "reverse"}
+
+// percentageStep steps the current percentage of a fan up or down to the next multiple of its `percentage_step`
+func percentageStep(state HassState, updown string) (int, error) {
+	// the percentage of a fan which is off may be unset
+	cur, _ := toFloat(state.Attributes["percentage"])
+	step, ok := toFloat(state.Attributes["percentage_step"])
+	if !ok || step <= 0 {
+		return 0, fmt.Errorf("state `%s` has no attribute `percentage_step`", state.EntityID)
+	}
+
+	// speeds of fans with e.g. 3 speeds are reported rounded (33, 67), so allow for some slack
+	const slack = 0.05
+	var k float64
+	switch updown {
+	case "+":
+		k = math.Floor(cur/step+slack) + 1
+	case "-":
+		k = math.Ceil(cur/step-slack) - 1
+	default:
+		return 0, fmt.Errorf("no such percentageStep: %s", updown)
+	}
+	return int(min(max(math.Round(k*step), 0), 100)), nil
+}
+
+// fanState resolves obj to a fan and returns its state, if it supports feature
+func (h *Hass) fanState(obj string, feature int, name string) (string, string, HassState, error) {
+	sub, obj, err := h.ResolveEntityInDomains(obj, "fan")
+	if err != nil {
+		return "", "", HassState{}, err
+	}
+	state, err := h.GetState(sub, obj)
+	if err != nil {
+		return "", "", HassState{}, err
+	}
+	if err := supportsFeature(state, feature, name); err != nil {
+		return "", "", HassState{}, err
+	}
+	return sub, obj, state, nil
+}
+
+func (h *Hass) fanCall(sub string, obj string, svc string, data map[string]any) ([]HassResult, error) {
+	payload := map[string]any{
+		"entity_id": fmt.Sprintf("%s.%s", sub, obj),
+	}
+	for k, v := range data {
+		payload[k] = v
+	}
+	res, err := h.callService(sub, svc, payload, true)
+	if err != nil {
+		return nil, err
+	}
+	return h.getResult(res)
+}
+
+// FanPercentage sets the speed of a fan, percentage is either 0-100, or +/- to step by `percentage_step`
+func (h *Hass) FanPercentage(obj string, percentage string) (string, string, string, []HassResult, error) {
+	sub, obj, state, err := h.fanState(obj, FanSetSpeed, "speed")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	var p int
+	switch percentage {
+	case "+", "-":
+		p, err = percentageStep(state, percentage)
+		if err != nil {
+			return "", "", "", nil, err
+		}
+	default:
+		p, err = strconv.Atoi(percentage)
+		if err != nil || p < 0 || p > 100 {
+			return "", "", "", nil, fmt.Errorf("percentage needs to be 0-100, or +/-")
+		}
+	}
+
+	results, err := h.fanCall(sub, obj, "set_percentage", map[string]any{"percentage": p})
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if p == 0 {
+		// fans turned off by 0 often report no percentage at all, so only wait for off
+		h.setTarget(sub, obj, "off", nil)
+	} else {
+		h.setTarget(sub, obj, "on", map[string]float64{"percentage": float64(p)})
+	}
+	return obj, fmt.Sprintf("speed set to %d%%", p), sub, results, nil
+}
+
+// FanPresetMode sets the preset mode of a fan, which has to be one of its `preset_modes`
+func (h *Hass) FanPresetMode(obj string, mode string) (string, string, string, []HassResult, error) {
+	sub, obj, state, err := h.fanState(obj, FanPresetMode, "preset modes")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if modes := FanPresetModes(state); !slices.Contains(modes, mode) {
+		return "", "", "", nil, fmt.Errorf("%s has no preset mode %s, available: %v", state.EntityID, mode, modes)
+	}
+
+	results, err := h.fanCall(sub, obj, "set_preset_mode", map[string]any{"preset_mode": mode})
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(sub, obj, "on", nil)
+	return obj, fmt.Sprintf("preset mode set to %s", mode), sub, results, nil
+}
+
+// FanOscillate turns the oscillation of a fan on or off
+func (h *Hass) FanOscillate(obj string, oscillating bool) (string, string, string, []HassResult, error) {
+	sub, obj, _, err := h.fanState(obj, FanOscillate, "oscillation")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	results, err := h.fanCall(sub, obj, "oscillate", map[string]any{"oscillating": oscillating})
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(sub, obj, "", nil)
+	if oscillating {
+		return obj, "oscillation turned on", sub, results, nil
+	}
+	return obj, "oscillation turned off", sub, results, nil
+}
+
+// FanDirection sets the direction of a fan to one of FanDirections
+func (h *Hass) FanDirection(obj string, direction string) (string, string, string, []HassResult, error) {
+	if !slices.Contains(FanDirections, direction) {
+		return "", "", "", nil, fmt.Errorf("direction needs to be one of %v", FanDirections)
+	}
+	sub, obj, _, err := h.fanState(obj, FanDirection, "direction")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	results, err := h.fanCall(sub, obj, "set_direction", map[string]any{"direction": direction})
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	h.setTarget(sub, obj, "", nil)
+	return obj, fmt.Sprintf("direction set to %s", direction), sub, results, nil
+}
+
+// FanPresetModes returns the `preset_modes` of a fan
+func FanPresetModes(state HassState) []string {
+	var modes []string
+	list, _ := state.Attributes["preset_modes"].([]any)
+	for _, m := range list {
+		if s, ok := m.(string); ok {
+			modes = append(modes, s)
+		}
+	}
+	return modes
+}
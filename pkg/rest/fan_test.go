@@ -0,0 +1,167 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_percentageStep(t *testing.T) {
+	tests := map[string]struct {
+		cur     any
+		step    any
+		updown  string
+		want    int
+		wantErr bool
+	}{
+		"up":               {cur: 42.0, step: 10.0, updown: "+", want: 50},
+		"up even":          {cur: 40.0, step: 10.0, updown: "+", want: 50},
+		"up max":           {cur: 100.0, step: 10.0, updown: "+", want: 100},
+		"down":             {cur: 42.0, step: 10.0, updown: "-", want: 40},
+		"down even":        {cur: 40.0, step: 10.0, updown: "-", want: 30},
+		"down min":         {cur: 0.0, step: 10.0, updown: "-", want: 0},
+		"off up":           {cur: nil, step: 25.0, updown: "+", want: 25},
+		"three speeds up":  {cur: 33.0, step: 100.0 / 3, updown: "+", want: 67},
+		"three speeds dn":  {cur: 67.0, step: 100.0 / 3, updown: "-", want: 33},
+		"three speeds top": {cur: 67.0, step: 100.0 / 3, updown: "+", want: 100},
+		"no step":          {cur: 40.0, step: nil, updown: "+", wantErr: true},
+		"invalid step":     {cur: 40.0, step: 10.0, updown: "*", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := HassState{EntityID: "fan.test", Attributes: map[string]any{"percentage": tt.cur, "percentage_step": tt.step}}
+			got, err := percentageStep(state, tt.updown)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Fan(t *testing.T) {
+	tests := map[string]struct {
+		action  func(h *Hass) (string, string, string, []HassResult, error)
+		want    string
+		wantErr bool
+	}{
+		"percentage": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPercentage("bedroom_fan", "70")
+			},
+			want: "speed set to 70%",
+		},
+		"percentage up": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPercentage("bedroom_fan", "+")
+			},
+			want: "speed set to 50%",
+		},
+		"percentage up from off": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) { return h.FanPercentage("attic_fan", "+") },
+			want:   "speed set to 33%",
+		},
+		"percentage out of range": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPercentage("bedroom_fan", "110")
+			},
+			wantErr: true,
+		},
+		"preset": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPresetMode("bedroom_fan", "sleep")
+			},
+			want: "preset mode set to sleep",
+		},
+		"unknown preset": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPresetMode("bedroom_fan", "turbo")
+			},
+			wantErr: true,
+		},
+		"preset unsupported": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPresetMode("attic_fan", "auto")
+			},
+			wantErr: true,
+		},
+		"oscillate": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanOscillate("bedroom_fan", true)
+			},
+			want: "oscillation turned on",
+		},
+		"oscillate unsupported": {
+			action:  func(h *Hass) (string, string, string, []HassResult, error) { return h.FanOscillate("attic_fan", true) },
+			wantErr: true,
+		},
+		"direction": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanDirection("bedroom_fan", "reverse")
+			},
+			want: "direction set to reverse",
+		},
+		"invalid direction": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanDirection("bedroom_fan", "up")
+			},
+			wantErr: true,
+		},
+		"no fan": {
+			action: func(h *Hass) (string, string, string, []HassResult, error) {
+				return h.FanPercentage("bedroom_main", "50")
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ms := hctltest.MockServer(t)
+			defer ms.Close()
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			obj, got, sub, results, err := tt.action(h)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sub != "fan" {
+				t.Errorf("got domain %s, want fan", sub)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if _, ok := FindResult(results, sub+"."+obj); !ok {
+				t.Errorf("no result for %s in %+v", obj, results)
+			}
+		})
+	}
+}
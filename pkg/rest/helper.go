@@ -15,10 +15,20 @@
 package rest
 
 import (
+	"fmt"
 	"slices"
 	"strings"
 )
 
+// supportsFeature returns an error if the entity does not support feature, a bit of its `supported_features`
+func supportsFeature(state HassState, feature int, name string) error {
+	features, _ := toFloat(state.Attributes["supported_features"])
+	if int(features)&feature == 0 {
+		return fmt.Errorf("%s does not support %s", state.EntityID, name)
+	}
+	return nil
+}
+
 func FilterDomainsFromStates(s []HassState, domains []string) []HassState {
 	if len(domains) == 0 {
 		return s
@@ -0,0 +1,84 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HistoryOptions are the parameters of /history/period
+type HistoryOptions struct {
+	Since time.Time
+	Until time.Time
+	// Minimal only returns state and last_changed for all but the first and last state
+	Minimal bool
+	// SignificantOnly leaves out attribute-only changes (Home Assistant's default)
+	SignificantOnly bool
+}
+
+// GetHistory returns the state changes of entities (resolved like in any other action)
+// from the recorder, one list per entity in chronological order
+func (h *Hass) GetHistory(entities []string, opts HistoryOptions) ([][]HassState, error) {
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("at least one entity is required")
+	}
+	var ids []string
+	for _, e := range entities {
+		d, n, err := h.ResolveEntity(e)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, fmt.Sprintf("%s.%s", d, n))
+	}
+
+	q := url.Values{}
+	q.Set("filter_entity_id", strings.Join(ids, ","))
+	if !opts.Until.IsZero() {
+		q.Set("end_time", opts.Until.UTC().Format(time.RFC3339))
+	}
+	if opts.Minimal {
+		q.Set("minimal_response", "")
+	}
+	if !opts.SignificantOnly {
+		q.Set("significant_changes_only", "0")
+	}
+
+	path := fmt.Sprintf("/history/period/%s?%s", url.PathEscape(opts.Since.UTC().Format(time.RFC3339)), q.Encode())
+	res, err := h.api("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var history [][]HassState
+	if err := json.Unmarshal(res, &history); err != nil {
+		return nil, err
+	}
+	for _, states := range history {
+		// minimal responses only carry the entity_id in the first state
+		if len(states) == 0 {
+			continue
+		}
+		for i := range states {
+			if states[i].EntityID == "" {
+				states[i].EntityID = states[0].EntityID
+			}
+		}
+	}
+	return history, nil
+}
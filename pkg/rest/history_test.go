@@ -0,0 +1,85 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_GetHistory(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	since := time.Date(2024, 10, 8, 18, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 10, 9, 8, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		entities []string
+		opts     HistoryOptions
+		want     []int
+		wantErr  string
+	}{
+		"no entity": {
+			wantErr: "at least one entity is required",
+		},
+		"unknown entity": {
+			entities: []string{"sensor.nonexisting"},
+			opts:     HistoryOptions{Since: since, Until: until},
+			wantErr:  "entity sensor.nonexisting does not exist",
+		},
+		"period": {
+			entities: []string{"garage_door", "sensor.outdoor_temperature"},
+			opts:     HistoryOptions{Since: since, Until: until, SignificantOnly: true},
+			want:     []int{4, 5},
+		},
+		"minimal": {
+			entities: []string{"sensor.outdoor_temperature"},
+			opts:     HistoryOptions{Since: since, Until: until, Minimal: true},
+			want:     []int{5},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			history, err := h.GetHistory(tt.entities, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != len(tt.want) {
+				t.Fatalf("got %d entities, want %d", len(history), len(tt.want))
+			}
+			for i, states := range history {
+				if len(states) != tt.want[i] {
+					t.Errorf("got %d states of %s, want %d", len(states), states[0].EntityID, tt.want[i])
+				}
+				for _, s := range states {
+					if s.EntityID == "" || s.State == "" || s.LastChanged == "" {
+						t.Errorf("incomplete state %+v", s)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// LogbookEntry is an entry of /logbook, the context fields tell what caused it
+type LogbookEntry struct {
+	When                string `json:"when"`
+	Name                string `json:"name"`
+	Message             string `json:"message,omitempty"`
+	EntityID            string `json:"entity_id,omitempty"`
+	State               string `json:"state,omitempty"`
+	Domain              string `json:"domain,omitempty"`
+	ContextUserID       string `json:"context_user_id,omitempty"`
+	ContextEventType    string `json:"context_event_type,omitempty"`
+	ContextDomain       string `json:"context_domain,omitempty"`
+	ContextService      string `json:"context_service,omitempty"`
+	ContextEntityID     string `json:"context_entity_id,omitempty"`
+	ContextEntityIDName string `json:"context_entity_id_name,omitempty"`
+	ContextName         string `json:"context_name,omitempty"`
+	ContextMessage      string `json:"context_message,omitempty"`
+}
+
+// Cause describes what led to the entry, e.g. the automation or the service call, empty if unknown
+func (e LogbookEntry) Cause() string {
+	switch {
+	case e.ContextEntityID != "" && e.ContextEntityIDName != "":
+		return fmt.Sprintf("%s (%s)", e.ContextEntityIDName, e.ContextEntityID)
+	case e.ContextEntityID != "":
+		return e.ContextEntityID
+	case e.ContextUserID != "":
+		return fmt.Sprintf("user %s", e.ContextUserID)
+	case e.ContextDomain != "" && e.ContextService != "":
+		return fmt.Sprintf("%s.%s", e.ContextDomain, e.ContextService)
+	}
+	return ""
+}
+
+// GetLogbook returns the logbook entries between since and until in chronological order
+// If entity is set (resolved like in any other action) only its entries are returned
+func (h *Hass) GetLogbook(entity string, since time.Time, until time.Time) ([]LogbookEntry, error) {
+	q := url.Values{}
+	q.Set("end_time", until.UTC().Format(time.RFC3339))
+	if entity != "" {
+		d, n, err := h.ResolveEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("entity", fmt.Sprintf("%s.%s", d, n))
+	}
+
+	path := fmt.Sprintf("/logbook/%s?%s", url.PathEscape(since.UTC().Format(time.RFC3339)), q.Encode())
+	res, err := h.api("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []LogbookEntry{}
+	if err := json.Unmarshal(res, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_GetLogbook(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	since := time.Date(2024, 10, 8, 18, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 10, 9, 6, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		entity  string
+		want    int
+		wantErr string
+	}{
+		"all": {
+			want: 5,
+		},
+		"entity": {
+			entity: "garage_door",
+			want:   2,
+		},
+		"unknown entity": {
+			entity:  "sensor.nonexisting",
+			wantErr: "entity sensor.nonexisting does not exist",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			entries, err := h.GetLogbook(tt.entity, since, until)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("got %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+}
+
+func Test_LogbookEntryCause(t *testing.T) {
+	tests := map[string]struct {
+		entry LogbookEntry
+		want  string
+	}{
+		"automation": {
+			LogbookEntry{ContextEntityID: "automation.some_automation", ContextEntityIDName: "Some automation", ContextDomain: "automation"},
+			"Some automation (automation.some_automation)",
+		},
+		"entity without name": {
+			LogbookEntry{ContextEntityID: "binary_sensor.garage_door"},
+			"binary_sensor.garage_door",
+		},
+		"user": {
+			LogbookEntry{ContextUserID: "abc", ContextDomain: "switch", ContextService: "turn_on"},
+			"user abc",
+		},
+		"service": {
+			LogbookEntry{ContextDomain: "switch", ContextService: "turn_on"},
+			"switch.turn_on",
+		},
+		"unknown": {
+			LogbookEntry{},
+			"",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.entry.Cause(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,28 +16,26 @@ package rest
 
 import "fmt"
 
-func (h *Hass) play(mediaURL string, mediaType string, sub string, obj string) error {
+func (h *Hass) play(mediaURL string, mediaType string, sub string, obj string) ([]HassResult, error) {
 	payload := map[string]any{
 		"entity_id":          fmt.Sprintf("%s.%s", sub, obj),
 		"media_content_id":   mediaURL,
 		"media_content_type": mediaType,
 	}
 
-	res, err := h.api("POST", fmt.Sprintf("/services/%s/play_media", sub), payload)
+	res, err := h.callService(sub, "play_media", payload, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := h.getResult(res); err != nil {
-		return err
-	}
-	return nil
+	return h.getResult(res)
 }
 
-func (h *Hass) PlayMusic(obj string, mediaURL string, name string) (string, string, string, error) {
+func (h *Hass) PlayMusic(obj string, mediaURL string, name string) (string, string, string, []HassResult, error) {
 	sub, obj, err := h.entityArgHandler([]string{obj}, "play_media")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
-	return obj, fmt.Sprintf("playing %s", name), sub, h.play(mediaURL, "music", sub, obj)
+	results, err := h.play(mediaURL, "music", sub, obj)
+	return obj, fmt.Sprintf("playing %s", name), sub, results, err
 }
@@ -27,7 +27,7 @@ func Test_PlayMusic(t *testing.T) {
 		Token:  "test_token",
 	}
 	defer ms.Close()
-	obj, action, sub, err := h.PlayMusic("player1", "testdata/fake.mp3", "fake.mp3")
+	obj, action, sub, results, err := h.PlayMusic("player1", "testdata/fake.mp3", "fake.mp3")
 	if err != nil {
 		t.Errorf("Error playing: %v", err)
 	}
@@ -40,4 +40,7 @@ func Test_PlayMusic(t *testing.T) {
 	if sub != "media_player" {
 		t.Errorf("got %s, want media_player", sub)
 	}
+	if r, ok := FindResult(results, sub+"."+obj); !ok || r.State != "idle" {
+		t.Errorf("got %+v, want result with state idle", results)
+	}
 }
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/lithammer/fuzzysearch/fuzzy"
@@ -34,15 +36,48 @@ type Hass struct {
 	States    []HassState
 	Services  []HassService
 	DeviceMap map[string]string
+	// Protected entities (like all entities of SecurityDomains) are never matched fuzzy,
+	// and services are only called for them if Confirm returns true
+	Protected []string
+	Confirm   ConfirmFunc
+	Client    *http.Client
+	Retry     Retry
 
 	Result HassResult
 }
 
+// SecurityDomains are domains whose entities are never matched fuzzy
+var SecurityDomains = []string{"lock", "alarm_control_panel"}
+
+// ErrNotExact is returned if fuzzy matching found a security device or protected entity
+var ErrNotExact = errors.New("security devices and protected entities are never matched fuzzy, use the exact name")
+
+// ConfirmFunc asks if service (e.g. lock.unlock) should really be called for the protected entityID
+type ConfirmFunc func(entityID string, service string) bool
+
+// ErrNotConfirmed is returned if a service call for a protected entity was not confirmed
+var ErrNotConfirmed = errors.New("not confirmed, use --yes to skip the confirmation")
+
+// ConfirmProtected asks confirm for each of entityIDs listed in protected
+// Without confirm, protected entities are never confirmed
+func ConfirmProtected(protected []string, confirm ConfirmFunc, service string, entityIDs ...string) error {
+	for _, e := range entityIDs {
+		if !slices.Contains(protected, e) {
+			continue
+		}
+		if confirm == nil || !confirm(e, service) {
+			return fmt.Errorf("%s is protected, %s %w", e, service, ErrNotConfirmed)
+		}
+	}
+	return nil
+}
+
 type HassResult struct {
-	EntityID    string `json:"entity_id"`
-	State       string `json:"state"`
-	TargetState string
-	Attributes  HassResponseAttributes `json:"attributes"`
+	EntityID         string `json:"entity_id"`
+	State            string `json:"state"`
+	TargetState      string
+	TargetAttributes map[string]float64
+	Attributes       HassResponseAttributes `json:"attributes"`
 }
 
 type HassResponseAttributes struct {
@@ -53,6 +88,14 @@ func New(apiURL string, token string, fuzz bool, deviceMap map[string]string) *H
 	return &Hass{APIURL: apiURL, Token: token, Fuzz: fuzz, DeviceMap: deviceMap}
 }
 
+// client returns the configured http client, or the default one
+func (h *Hass) client() *http.Client {
+	if h.Client != nil {
+		return h.Client
+	}
+	return http.DefaultClient
+}
+
 func (h *Hass) preflight() error {
 	if h.APIURL == "" {
 		return errors.New("no Hub URL found: Run `hctl init` or manually create config")
@@ -78,54 +121,164 @@ func (h *Hass) createRequest(meth string, path string, payload map[string]any) (
 }
 
 func (h *Hass) api(meth string, path string, payload map[string]any) ([]byte, error) {
+	return h.request(meth, path, payload, meth == http.MethodGet)
+}
+
+// callService posts payload to domain.service, after calls for protected entities were confirmed
+// All service calls go through here, so no action skips the confirmation
+func (h *Hass) callService(domain string, service string, payload map[string]any, idempotent bool) ([]byte, error) {
+	var entityIDs []string
+	switch e := payload["entity_id"].(type) {
+	case string:
+		entityIDs = []string{e}
+	case []string:
+		entityIDs = e
+	case []any:
+		// e.g. a list decoded from JSON
+		for _, id := range e {
+			entityIDs = append(entityIDs, fmt.Sprint(id))
+		}
+	}
+	if err := ConfirmProtected(h.Protected, h.Confirm, fmt.Sprintf("%s.%s", domain, service), entityIDs...); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/services/%s/%s", domain, service)
+	if idempotent {
+		return h.apiIdempotent("POST", path, payload)
+	}
+	return h.api("POST", path, payload)
+}
+
+// apiIdempotent is like api, but also retries requests other than GET,
+// use for service calls that can safely be sent more than once (e.g. turn_off)
+func (h *Hass) apiIdempotent(meth string, path string, payload map[string]any) ([]byte, error) {
+	return h.request(meth, path, payload, true)
+}
+
+func (h *Hass) request(meth string, path string, payload map[string]any, idempotent bool) ([]byte, error) {
 	if err := h.preflight(); err != nil {
 		return nil, err
 	}
 
-	req, err := h.createRequest(meth, path, payload)
+	retry := h.Retry
+	if !idempotent {
+		retry.Retries = 0
+	}
+
+	rData, status, err := retry.Do(path, func() ([]byte, int, error) {
+		return h.do(meth, path, payload)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return rData, checkStatus(status, path)
+}
+
+func (h *Hass) do(meth string, path string, payload map[string]any) ([]byte, int, error) {
+	req, err := h.createRequest(meth, path, payload)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	log.Info().Msgf("Requesting URL %s, Method %s, Payload: %#v", req.URL, req.Method, payload)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", h.Token))
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := h.client().Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	defer res.Body.Close()
 
 	rData, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	return rData, res.StatusCode, nil
+}
 
-	if res.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("authentication failed: invalid or expired token")
-	}
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("API endpoint not found (404): %s", path)
+// StatusError is returned if the hub answered with another response code than 200
+type StatusError struct {
+	Status int
+	Path   string
+}
+
+func (e *StatusError) Error() string {
+	switch e.Status {
+	case http.StatusUnauthorized:
+		return "authentication failed: invalid or expired token"
+	case http.StatusNotFound:
+		return fmt.Sprintf("API endpoint not found (404): %s", e.Path)
 	}
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected API response code %d for %s", res.StatusCode, path)
+	return fmt.Sprintf("unexpected API response code %d for %s", e.Status, e.Path)
+}
+
+func checkStatus(status int, path string) error {
+	if status != http.StatusOK {
+		return &StatusError{Status: status, Path: path}
 	}
+	return nil
+}
 
-	return rData, nil
+// Transient reports whether err is a network or server error, so the same request
+// might succeed later, while e.g. an invalid token or an unknown entity won't
+func Transient(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return retryable(se.Status, nil)
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
 }
 
-// TODO: Rework to return result list and work with it
-func (h *Hass) getResult(res []byte) error {
+// getResult parses the response of a service call, which lists all states
+// that changed while the service was executed
+func (h *Hass) getResult(res []byte) ([]HassResult, error) {
 	var result []HassResult
 
 	if err := json.Unmarshal(res, &result); err != nil {
 		log.Debug().Caller().Msgf("Failed to Unmarshal: %+v", string(res))
-		return err
+		return nil, err
 	}
 
 	log.Debug().Caller().Msgf("Result: %#v", result)
-	return nil
+	return result, nil
+}
+
+// FindResult returns the result of entityID (domain.name) from a service call response
+// If the hub did not report a change for entityID, false is returned
+func FindResult(results []HassResult, entityID string) (HassResult, bool) {
+	for _, r := range results {
+		if r.EntityID == entityID {
+			return r, true
+		}
+	}
+	return HassResult{}, false
+}
+
+// MergeResult returns results with the result of r.EntityID replaced by r (e.g. the
+// state confirmed by Wait), or r added if the hub did not report a change for it
+func MergeResult(results []HassResult, r HassResult) []HassResult {
+	merged := make([]HassResult, 0, len(results)+1)
+	found := false
+	for _, res := range results {
+		if res.EntityID == r.EntityID {
+			res, found = r, true
+		}
+		merged = append(merged, res)
+	}
+	if !found {
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Name returns the friendly name of the result together with obj, or just obj if there is none
+func (r HassResult) Name(obj string) string {
+	if r.Attributes.FriendlyName == "" || r.Attributes.FriendlyName == obj {
+		return obj
+	}
+	return fmt.Sprintf("%s (%s)", r.Attributes.FriendlyName, obj)
 }
 
 func getFuzz(name string, names []string) (int, bool) {
@@ -150,24 +303,32 @@ func getFuzz(name string, names []string) (int, bool) {
 	return position, distance > -1
 }
 
-func (h *Hass) resolveMapping(domain, name string) (string, string) {
+func (h *Hass) resolveMapping(domain, name string) (string, string, bool) {
 	if val, ok := h.DeviceMap[name]; ok && domain == "" {
 		log.Debug().Caller().Msgf("Found `%s` in device_map: %s", name, val)
 		domain, name = splitDomainAndName(val)
-		h.Fuzz = false
+		return domain, name, true
 	}
-	return domain, name
+	return domain, name, false
 }
 
-// Find matching entity for provided service
+// Find matching entity for provided service, or in all states if service is empty
 // Return error if none has been found
 func (h *Hass) findEntity(name string, domain string, service string) (string, string, error) {
-	states, err := h.GetStatesWithService(service)
+	var states []HassState
+	var err error
+	if service == "" {
+		states, err = h.GetStates()
+	} else {
+		states, err = h.GetStatesWithService(service)
+	}
 	if err != nil {
 		return "", "", err
 	}
 
-	domain, name = h.resolveMapping(domain, name)
+	domain, name, mapped := h.resolveMapping(domain, name)
+	// never fuzz a name we got from device_map
+	fuzz := h.Fuzz && !mapped
 
 	var names []string
 
@@ -184,33 +345,44 @@ func (h *Hass) findEntity(name string, domain string, service string) (string, s
 		}
 
 		// add to fuzz checker names list when fuzz enabled
-		if h.Fuzz {
+		if fuzz {
 			names = append(names, n)
 		}
 	}
 
 	// when fuzz enabled
-	if h.Fuzz {
+	if fuzz {
 		if p, ok := getFuzz(name, names); ok {
-			d, n := splitDomainAndName(states[p].EntityID)
 			// get domain and entity name from original states array by position
-			return d, n, nil
+			for i := range states {
+				d, n := splitDomainAndName(states[i].EntityID)
+				if (domain == "" || domain == d) && n == names[p] {
+					if h.isProtected(d, n) {
+						return "", "", fmt.Errorf("%s matches %s.%s: %w", name, d, n, ErrNotExact)
+					}
+					return d, n, nil
+				}
+			}
 		}
 	}
 	// Entity not found in service-filtered states. Determine why.
-	name, err = h.fuzzyResolveFromAllStates(name, domain)
-	if err != nil {
-		return "", "", err
+	if fuzz {
+		name, err = h.fuzzyResolveFromAllStates(name, domain)
+		if err != nil {
+			return "", "", err
+		}
 	}
 	return "", "", h.entityNotFoundError(name, domain, service)
 }
 
+// isProtected returns true for entities of SecurityDomains and protected entities
+func (h *Hass) isProtected(domain, name string) bool {
+	return slices.Contains(SecurityDomains, domain) || slices.Contains(h.Protected, fmt.Sprintf("%s.%s", domain, name))
+}
+
 // fuzzyResolveFromAllStates tries to resolve name against all states via fuzzy
 // matching so that error messages reference the actual entity name.
 func (h *Hass) fuzzyResolveFromAllStates(name, domain string) (string, error) {
-	if !h.Fuzz {
-		return name, nil
-	}
 	allStates, err := h.GetStates()
 	if err != nil {
 		return "", err
@@ -236,7 +408,7 @@ func (h *Hass) entityNotFoundError(name, domain, service string) error {
 	if err != nil {
 		return err
 	}
-	if !exists {
+	if !exists || service == "" {
 		if domain != "" {
 			return fmt.Errorf("entity %s.%s does not exist", domain, name)
 		}
@@ -277,6 +449,47 @@ func (h *Hass) entityArgHandler(args []string, service string) (string, string,
 	return "", "", fmt.Errorf("entityArgHandler has to many entries in args: %d", len(args))
 }
 
+// ResolveEntity resolves a (short, mapped or fuzzy) name to an existing entity
+// Returns domain, name
+func (h *Hass) ResolveEntity(name string) (string, string, error) {
+	return h.entityArgHandler([]string{name}, "")
+}
+
+// ResolveEntityForService is like ResolveEntity, but only considers entities
+// of domains offering service, args are either [name] or [domain, name]
+// Returns domain, name
+func (h *Hass) ResolveEntityForService(args []string, service string) (string, string, error) {
+	return h.entityArgHandler(args, service)
+}
+
+// ResolveEntityInDomains is ResolveEntity for entities of domains only
+// Names without domain are tried in each of the domains, so fuzzy matching never picks an entity of another domain
+// Returns domain, name
+func (h *Hass) ResolveEntityInDomains(name string, domains ...string) (string, string, error) {
+	// a fuzzy match of a protected entity is kept as error, so the user learns why
+	var notExact error
+	d, n, err := h.ResolveEntity(name)
+	if err == nil && slices.Contains(domains, d) {
+		return d, n, nil
+	} else if errors.Is(err, ErrNotExact) {
+		notExact = err
+	}
+	if domain, _ := splitDomainAndName(name); domain == "" {
+		for _, d := range domains {
+			d, n, err := h.ResolveEntity(fmt.Sprintf("%s.%s", d, name))
+			if err == nil {
+				return d, n, nil
+			} else if errors.Is(err, ErrNotExact) {
+				notExact = err
+			}
+		}
+	}
+	if notExact != nil {
+		return "", "", notExact
+	}
+	return "", "", fmt.Errorf("no %s entity found for %s", strings.Join(domains, " or "), name)
+}
+
 // Returns domain, name
 func splitDomainAndName(s string) (string, string) {
 	p := strings.Split(s, ".")
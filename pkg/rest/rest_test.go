@@ -15,6 +15,7 @@
 package rest
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -136,6 +137,85 @@ func Test_findEntity_FuzzyErrorResolution(t *testing.T) {
 	}
 }
 
+func Test_ResolveEntity(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := &Hass{
+		APIURL:    ms.URL,
+		Token:     "test_token",
+		Fuzz:      true,
+		DeviceMap: map[string]string{"lm": "light.livingroom_main"},
+		Protected: []string{"button.restart_router"},
+	}
+
+	tests := map[string]struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		"security device": {
+			name: "shed",
+			want: "lock.shed",
+		},
+		"security device not fuzzy": {
+			name:    "lock.frnt",
+			wantErr: "frnt matches lock.front_door: " + ErrNotExact.Error(),
+		},
+		"protected entity": {
+			name: "restart_router",
+			want: "button.restart_router",
+		},
+		"protected entity not fuzzy": {
+			name:    "button.restart_rout",
+			wantErr: "restart_rout matches button.restart_router: " + ErrNotExact.Error(),
+		},
+		"short name": {
+			name: "bedroom_main",
+			want: "light.bedroom_main",
+		},
+		"qualified name": {
+			name: "switch.bedroom_warp",
+			want: "switch.bedroom_warp",
+		},
+		"device map": {
+			name: "lm",
+			want: "light.livingroom_main",
+		},
+		"fuzzy": {
+			name: "some_auto",
+			want: "automation.some_automation",
+		},
+		"fuzzy within domain": {
+			name: "switch.lw",
+			want: "switch.livingroom_warp",
+		},
+		"does not exist": {
+			name:    "zzzzzzzzz",
+			wantErr: "entity zzzzzzzzz does not exist",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, n, err := h.ResolveEntity(tt.name)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Errorf("expected error %q, got nil", tt.wantErr)
+				} else if err.Error() != tt.wantErr {
+					t.Errorf("got error %q, want %q", err.Error(), tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+			if got := d + "." + n; got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_api_HTTPErrors(t *testing.T) {
 	tests := map[string]struct {
 		statusCode int
@@ -180,3 +260,138 @@ func Test_api_HTTPErrors(t *testing.T) {
 		})
 	}
 }
+
+func Test_ResolveEntityInDomains_NotExact(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := &Hass{APIURL: ms.URL, Token: "test_token", Fuzz: true}
+
+	if _, _, err := h.ResolveEntityInDomains("frontdoor", "lock"); !errors.Is(err, ErrNotExact) {
+		t.Errorf("got error %v, want %v", err, ErrNotExact)
+	}
+	// exact names are fine, even if another domain has the same name
+	d, n, err := h.ResolveEntityInDomains("front_door", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != "lock" || n != "front_door" {
+		t.Errorf("got %s.%s, want lock.front_door", d, n)
+	}
+}
+
+func Test_FindResult(t *testing.T) {
+	results := []HassResult{
+		{EntityID: "switch.kitchen", State: "off"},
+		{EntityID: "light.kitchen", State: "on"},
+	}
+	if r, ok := FindResult(results, "light.kitchen"); !ok || r.State != "on" {
+		t.Errorf("got %+v, want light.kitchen on", r)
+	}
+	// short names are ambiguous, they never match
+	if r, ok := FindResult(results, "kitchen"); ok {
+		t.Errorf("got %+v, want no result", r)
+	}
+}
+
+func Test_MergeResult(t *testing.T) {
+	waited := HassResult{EntityID: "light.kitchen", State: "on"}
+	tests := map[string]struct {
+		results []HassResult
+		want    []HassResult
+	}{
+		"replace": {
+			results: []HassResult{{EntityID: "switch.kitchen", State: "off"}, {EntityID: "light.kitchen", State: "off"}},
+			want:    []HassResult{{EntityID: "switch.kitchen", State: "off"}, waited},
+		},
+		"no change reported": {
+			results: nil,
+			want:    []HassResult{waited},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := MergeResult(tt.results, waited)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].EntityID != tt.want[i].EntityID || got[i].State != tt.want[i].State {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_callService_Protected(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		confirm ConfirmFunc
+		call    func(h *Hass) error
+		asked   string
+		wantErr bool
+	}{
+		"toggle without confirm": {
+			call: func(h *Hass) error {
+				_, _, _, _, err := h.Toggle("switch.livingroom_warp")
+				return err
+			},
+			wantErr: true,
+		},
+		"toggle not confirmed": {
+			confirm: func(string, string) bool { return false },
+			call: func(h *Hass) error {
+				_, _, _, _, err := h.Toggle("switch.livingroom_warp")
+				return err
+			},
+			asked:   "switch.livingroom_warp switch.toggle",
+			wantErr: true,
+		},
+		"call service confirmed": {
+			confirm: func(string, string) bool { return true },
+			call: func(h *Hass) error {
+				_, err := h.CallService("lock", "unlock", []string{"front_door"}, nil)
+				return err
+			},
+			asked: "lock.front_door lock.unlock",
+		},
+		"call service with entities from json": {
+			call: func(h *Hass) error {
+				_, err := h.CallService("lock", "unlock", nil, map[string]any{"entity_id": []any{"lock.front_door"}})
+				return err
+			},
+			wantErr: true,
+		},
+		"not protected": {
+			call: func(h *Hass) error {
+				_, _, _, _, err := h.TurnOn("bedroom_warp")
+				return err
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var asked string
+			h := &Hass{APIURL: ms.URL, Token: "test_token", Protected: []string{"switch.livingroom_warp", "lock.front_door"}}
+			if tt.confirm != nil {
+				h.Confirm = func(entityID string, service string) bool {
+					asked = entityID + " " + service
+					return tt.confirm(entityID, service)
+				}
+			}
+			err := tt.call(h)
+			if tt.wantErr && !errors.Is(err, ErrNotConfirmed) {
+				t.Errorf("got error %v, want %v", err, ErrNotConfirmed)
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+			if asked != tt.asked {
+				t.Errorf("asked %q, want %q", asked, tt.asked)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// sleep is replaced in tests
+var sleep = time.Sleep
+
+// Retry configures retries of idempotent requests
+type Retry struct {
+	Retries    int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// delay returns the exponential backoff for the given attempt (starting at 0),
+// capped at MaxBackoff and with jitter of up to half the delay
+func (r Retry) delay(attempt int) time.Duration {
+	d := r.Backoff
+	for i := 0; i < attempt && (r.MaxBackoff <= 0 || d < r.MaxBackoff); i++ {
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	//nolint:gosec
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable reports whether a request failed with a network error or a server error,
+// e.g. while Home Assistant is restarting or a reverse proxy returns 502/503
+func retryable(status int, err error) bool {
+	if err != nil {
+		return true
+	}
+	return status >= http.StatusInternalServerError
+}
+
+// Do sends a request with req and retries it on network and server errors,
+// at most r.Retries times, path is only used for logging
+// Returns the body and status code of the last attempt
+func (r Retry) Do(path string, req func() ([]byte, int, error)) ([]byte, int, error) {
+	for attempt := 0; ; attempt++ {
+		data, status, err := req()
+		if attempt >= r.Retries || !retryable(status, err) {
+			return data, status, err
+		}
+		d := r.delay(attempt)
+		if err != nil {
+			log.Warn().Msgf("Request to %s failed (%v), retrying in %s (%d/%d)", path, err, d.Round(time.Millisecond), attempt+1, r.Retries)
+		} else {
+			log.Warn().Msgf("Request to %s failed with response code %d, retrying in %s (%d/%d)", path, status, d.Round(time.Millisecond), attempt+1, r.Retries)
+		}
+		sleep(d)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_api_Retry(t *testing.T) {
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	tests := map[string]struct {
+		method     string
+		idempotent bool
+		failures   int32
+		retries    int
+		wantCalls  int32
+		wantErr    string
+	}{
+		"get recovers": {
+			method:    "GET",
+			failures:  2,
+			retries:   3,
+			wantCalls: 3,
+		},
+		"get gives up": {
+			method:    "GET",
+			failures:  5,
+			retries:   2,
+			wantCalls: 3,
+			wantErr:   "unexpected API response code 503 for /test",
+		},
+		"post is not retried": {
+			method:    "POST",
+			failures:  1,
+			retries:   3,
+			wantCalls: 1,
+			wantErr:   "unexpected API response code 503 for /test",
+		},
+		"idempotent post recovers": {
+			method:     "POST",
+			idempotent: true,
+			failures:   1,
+			retries:    3,
+			wantCalls:  2,
+		},
+		"no retries configured": {
+			method:    "GET",
+			failures:  1,
+			retries:   0,
+			wantCalls: 1,
+			wantErr:   "unexpected API response code 503 for /test",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ms.Close()
+
+			h := &Hass{
+				APIURL: ms.URL,
+				Token:  "test_token",
+				Retry:  Retry{Retries: tt.retries, Backoff: time.Millisecond},
+			}
+
+			var err error
+			if tt.idempotent {
+				_, err = h.apiIdempotent(tt.method, "/test", nil)
+			} else {
+				_, err = h.api(tt.method, "/test", nil)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got error %q, want none", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_api_RetryNetworkError(t *testing.T) {
+	var slept int
+	sleep = func(time.Duration) { slept++ }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ms.Close()
+
+	h := &Hass{
+		APIURL: ms.URL,
+		Token:  "test_token",
+		Retry:  Retry{Retries: 2, Backoff: time.Millisecond},
+	}
+	if _, err := h.api("GET", "/test", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if slept != 2 {
+		t.Errorf("got %d retries, want 2", slept)
+	}
+}
+
+func Test_Retry_delay(t *testing.T) {
+	r := Retry{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := map[string]struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		"first":  {0, 50 * time.Millisecond, 100 * time.Millisecond},
+		"third":  {2, 200 * time.Millisecond, 400 * time.Millisecond},
+		"capped": {10, 500 * time.Millisecond, time.Second},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				if d := r.delay(tt.attempt); d < tt.min || d > tt.max {
+					t.Errorf("got %s, want between %s and %s", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -22,7 +22,7 @@ import (
 )
 
 const (
-	serviceCount = 47
+	serviceCount = 48
 )
 
 func Test_GetServices(t *testing.T) {
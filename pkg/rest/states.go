@@ -24,9 +24,11 @@ import (
 )
 
 type HassState struct {
-	EntityID   string         `json:"entity_id"`
-	State      string         `json:"state"`
-	Attributes map[string]any `json:"attributes"`
+	EntityID    string         `json:"entity_id"`
+	State       string         `json:"state"`
+	Attributes  map[string]any `json:"attributes"`
+	LastChanged string         `json:"last_changed,omitempty"`
+	LastUpdated string         `json:"last_updated,omitempty"`
 }
 
 // TODO: Add sort, configurable, maybe even something like GetSortedStates(order ...string)?
@@ -55,6 +57,12 @@ func (h *Hass) GetStates() ([]HassState, error) {
 	return states, nil
 }
 
+// UseCache makes h serve states and services from the completion cache
+func (h *Hass) UseCache(states []HassState, services []HassService) {
+	h.States = states
+	h.Services = services
+}
+
 func (h *Hass) GetState(domain, name string) (HassState, error) {
 	states, err := h.GetStates()
 	if err != nil {
@@ -22,7 +22,7 @@ import (
 )
 
 const (
-	statesCount = 11
+	statesCount = 29
 )
 
 func Test_GetStates(t *testing.T) {
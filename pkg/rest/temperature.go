@@ -14,27 +14,36 @@
 
 package rest
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
-func (h *Hass) TemperatureSet(obj string, temp float64) (string, string, string, error) {
+func (h *Hass) TemperatureSet(obj string, temp float64) (string, string, string, []HassResult, error) {
 	svc := "set_temperature"
 	sub, obj, err := h.entityArgHandler([]string{obj}, svc)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 
+	tempStr := fmt.Sprintf("%.1f", temp)
 	payload := map[string]any{
 		"entity_id":   fmt.Sprintf("%s.%s", sub, obj),
-		"temperature": fmt.Sprintf("%.1f", temp),
+		"temperature": tempStr,
 	}
-	res, err := h.api("POST", fmt.Sprintf("/services/%s/%s", sub, svc), payload)
+	res, err := h.callService(sub, svc, payload, true)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
-
-	if err := h.getResult(res); err != nil {
-		return "", "", "", err
+	target, err := strconv.ParseFloat(tempStr, 64)
+	if err != nil {
+		return "", "", "", nil, err
 	}
-	return obj, fmt.Sprintf("temperature set to %.1f", temp), sub, nil
+	h.setTarget(sub, obj, "", map[string]float64{"temperature": target})
 
+	results, err := h.getResult(res)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	return obj, fmt.Sprintf("temperature set to %.1f", temp), sub, results, nil
 }
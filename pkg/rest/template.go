@@ -0,0 +1,49 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// RenderTemplate renders a Jinja template on the hub and returns the raw result
+func (h *Hass) RenderTemplate(template string) (string, error) {
+	if err := h.preflight(); err != nil {
+		return "", err
+	}
+	path := "/template"
+	// rendering has no side effects, so it can safely be retried
+	res, status, err := h.Retry.Do(path, func() ([]byte, int, error) {
+		return h.do("POST", path, map[string]any{"template": template})
+	})
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusBadRequest {
+		// the hub explains what's wrong with the template
+		var msg struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(res, &msg); err == nil && msg.Message != "" {
+			return "", errors.New(msg.Message)
+		}
+	}
+	if err := checkStatus(status, path); err != nil {
+		return "", err
+	}
+	return string(res), nil
+}
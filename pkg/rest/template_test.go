@@ -0,0 +1,60 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_RenderTemplate(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		template string
+		want     string
+		wantErr  string
+	}{
+		"state": {
+			template: `It is {{ states("sensor.outdoor_temperature") }} outside`,
+			want:     "It is 14.2 outside",
+		},
+		"syntax error": {
+			template: `{{ states("sensor.outdoor_temperature") }`,
+			wantErr:  "Error rendering template: TemplateSyntaxError: unexpected '}'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{APIURL: ms.URL, Token: "test_token"}
+			got, err := h.RenderTemplate(tt.template)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
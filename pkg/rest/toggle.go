@@ -16,25 +16,35 @@ package rest
 
 import "fmt"
 
-func (h *Hass) toggle(sub string, obj string) error {
+var toggleTarget = map[string]string{
+	"on":  "off",
+	"off": "on",
+}
+
+func (h *Hass) toggle(sub string, obj string) ([]HassResult, error) {
+	// remember what toggle is expected to result in before toggling
+	var target string
+	if current, err := h.GetState(sub, obj); err == nil {
+		target = toggleTarget[current.State]
+	}
+
 	payload := map[string]any{
 		"entity_id": fmt.Sprintf("%s.%s", sub, obj),
 	}
-	res, err := h.api("POST", fmt.Sprintf("/services/%s/toggle", sub), payload)
+	res, err := h.callService(sub, "toggle", payload, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	h.setTarget(sub, obj, target, nil)
 
-	if err := h.getResult(res); err != nil {
-		return err
-	}
-	return nil
+	return h.getResult(res)
 }
 
-func (h *Hass) Toggle(args ...string) (string, string, string, error) {
+func (h *Hass) Toggle(args ...string) (string, string, string, []HassResult, error) {
 	sub, obj, err := h.entityArgHandler(args, "toggle")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
-	return obj, "toggle", sub, h.toggle(sub, obj)
+	results, err := h.toggle(sub, obj)
+	return obj, "toggle", sub, results, err
 }
@@ -27,7 +27,7 @@ func Test_Toggle(t *testing.T) {
 		Token:  "test_token",
 	}
 	defer ms.Close()
-	obj, action, sub, err := h.Toggle("bedroom_main")
+	obj, action, sub, results, err := h.Toggle("bedroom_main")
 	if err != nil {
 		t.Errorf("Error toggling: %v", err)
 	}
@@ -40,4 +40,15 @@ func Test_Toggle(t *testing.T) {
 	if sub != "light" {
 		t.Errorf("got %s, want light", sub)
 	}
+	// bedroom_main is on in testdata
+	r, ok := FindResult(results, sub+"."+obj)
+	if !ok {
+		t.Fatalf("no result for %s in %+v", obj, results)
+	}
+	if r.State != "off" {
+		t.Errorf("got state %s, want off", r.State)
+	}
+	if r.Name(obj) != "Bedroom Main (bedroom_main)" {
+		t.Errorf("got name %s, want Bedroom Main (bedroom_main)", r.Name(obj))
+	}
 }
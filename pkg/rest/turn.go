@@ -25,7 +25,7 @@ func kelvinToMired(k int) int {
 	return int(math.Round(1_000_000.0 / float64(k)))
 }
 
-func (h *Hass) turn(state, domain, device, brightness string, rgb []int, colorTemp int, transition float64) error {
+func (h *Hass) turn(state, domain, device, brightness string, rgb []int, colorTemp int, transition float64) ([]HassResult, error) {
 	// if err := h.checkEntity(sub, fmt.Sprintf("turn_%s", state), obj); err != nil {
 	// 	return err
 	// }
@@ -46,32 +46,41 @@ func (h *Hass) turn(state, domain, device, brightness string, rgb []int, colorTe
 		payload["transition"] = transition
 	}
 
-	res, err := h.api("POST", fmt.Sprintf("/services/%s/turn_%s", domain, state), payload)
+	res, err := h.callService(domain, "turn_"+state, payload, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := h.getResult(res); err != nil {
-		return err
+	targetAttributes := map[string]float64{}
+	if b, err := strconv.ParseFloat(brightness, 64); err == nil {
+		targetAttributes["brightness"] = b
 	}
+	target := state
+	if state == "on" {
+		// e.g. media players are idle or playing, climate is heat or cool
+		target = onTarget(domain)
+	}
+	h.setTarget(domain, device, target, targetAttributes)
 
-	return nil
+	return h.getResult(res)
 }
 
-func (h *Hass) TurnOff(args ...string) (string, string, string, error) {
+func (h *Hass) TurnOff(args ...string) (string, string, string, []HassResult, error) {
 	sub, obj, err := h.entityArgHandler(args, "turn_off")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
-	return obj, "off", sub, h.turn("off", sub, obj, "", nil, 0, 0)
+	results, err := h.turn("off", sub, obj, "", nil, 0, 0)
+	return obj, "off", sub, results, err
 }
 
-func (h *Hass) TurnOn(args ...string) (string, string, string, error) {
+func (h *Hass) TurnOn(args ...string) (string, string, string, []HassResult, error) {
 	sub, obj, err := h.entityArgHandler(args, "turn_on")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
-	return obj, "on", sub, h.turn("on", sub, obj, "", nil, 0, 0)
+	results, err := h.turn("on", sub, obj, "", nil, 0, 0)
+	return obj, "on", sub, results, err
 }
 
 func (h *Hass) brightStep(domain, device, updown string) (string, error) {
@@ -167,26 +176,26 @@ func (h *Hass) handleBrightness(domain, device, brightness string) (string, erro
 	return brightness, err
 }
 
-func (h *Hass) TurnLightOnCustom(device, brightness, color string, colorTemp int, transition float64) (string, string, string, error) {
+func (h *Hass) TurnLightOnCustom(device, brightness, color string, colorTemp int, transition float64) (string, string, string, []HassResult, error) {
 	domain, device, err := h.entityArgHandler([]string{device}, "turn_on")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 
 	if color != "" && colorTemp != 0 {
-		return "", "", "", fmt.Errorf("cannot specify both RGB color and color temperature at the same time")
+		return "", "", "", nil, fmt.Errorf("cannot specify both RGB color and color temperature at the same time")
 	}
 
 	brightness, err = h.handleBrightness(domain, device, brightness)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 
 	var rgb []int
 	if color != "" {
 		rgb, err = parseRGB(color)
 		if err != nil {
-			return "", "", "", err
+			return "", "", "", nil, err
 		}
 	}
 
@@ -194,37 +203,39 @@ func (h *Hass) TurnLightOnCustom(device, brightness, color string, colorTemp int
 	if brightness != "" {
 		brightnessScaled, err = scaleBrightness(brightness)
 		if err != nil {
-			return "", "", "", err
+			return "", "", "", nil, err
 		}
 	}
 
 	if colorTemp != 0 {
 		if colorTemp < 1000 || colorTemp > 10000 {
-			return "", "", "", fmt.Errorf("color temperature must be 1000-10000 K")
+			return "", "", "", nil, fmt.Errorf("color temperature must be 1000-10000 K")
 		}
 		colorTemp = kelvinToMired(colorTemp)
 	}
 
-	return device, "on", domain, h.turn("on", domain, device, brightnessScaled, rgb, colorTemp, transition)
+	results, err := h.turn("on", domain, device, brightnessScaled, rgb, colorTemp, transition)
+	return device, "on", domain, results, err
 }
 
-func (h *Hass) TurnLightOffTransition(device string, transition float64) (string, string, string, error) {
+func (h *Hass) TurnLightOffTransition(device string, transition float64) (string, string, string, []HassResult, error) {
 	domain, device, err := h.entityArgHandler([]string{device}, "turn_off")
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 
-	return device, "off", domain, h.turn("off", domain, device, "", nil, 0, transition)
+	results, err := h.turn("off", domain, device, "", nil, 0, transition)
+	return device, "off", domain, results, err
 }
 
-func (h *Hass) TurnLightOff(obj string) (string, string, string, error) {
+func (h *Hass) TurnLightOff(obj string) (string, string, string, []HassResult, error) {
 	return h.TurnOff("light", obj)
 }
 
-func (h *Hass) TurnLightOn(obj string) (string, string, string, error) {
+func (h *Hass) TurnLightOn(obj string) (string, string, string, []HassResult, error) {
 	return h.TurnOn("light", obj)
 }
 
-func (h *Hass) ToggleLight(obj string) (string, string, string, error) {
+func (h *Hass) ToggleLight(obj string) (string, string, string, []HassResult, error) {
 	return h.Toggle("light", obj)
 }
@@ -16,24 +16,26 @@ package rest
 
 import "fmt"
 
-func (h *Hass) VolumeSet(obj string, volume int) (string, string, string, error) {
+func (h *Hass) VolumeSet(obj string, volume int) (string, string, string, []HassResult, error) {
 	svc := "volume_set"
 	sub, obj, err := h.entityArgHandler([]string{obj}, svc)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 
 	payload := map[string]any{
 		"entity_id":    fmt.Sprintf("%s.%s", sub, obj),
 		"volume_level": fmt.Sprintf("%.2f", float32(volume)/100),
 	}
-	res, err := h.api("POST", fmt.Sprintf("/services/%s/%s", sub, svc), payload)
+	res, err := h.callService(sub, svc, payload, true)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
+	h.setTarget(sub, obj, "", map[string]float64{"volume_level": float64(volume) / 100})
 
-	if err := h.getResult(res); err != nil {
-		return "", "", "", err
+	results, err := h.getResult(res)
+	if err != nil {
+		return "", "", "", nil, err
 	}
-	return obj, fmt.Sprintf("volume set to %d%%", volume), sub, nil
+	return obj, fmt.Sprintf("volume set to %d%%", volume), sub, results, nil
 }
@@ -0,0 +1,173 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const waitInterval = 250 * time.Millisecond
+
+// targetTolerance for numeric target attributes, as the hub may round values
+var targetTolerance = map[string]float64{
+	"brightness":            2,
+	"volume_level":          0.01,
+	"temperature":           0.05,
+	"current_position":      1,
+	"current_tilt_position": 1,
+	"percentage":            1,
+}
+
+// notOff is the target state of entities turned on whose state is not on (e.g. playing or heat)
+const notOff = "!off"
+
+// OnDomains are domains whose entities are in state on after turn_on
+var OnDomains = []string{"automation", "fan", "humidifier", "input_boolean", "light", "remote", "siren", "switch"}
+
+// onTarget returns the state an entity of domain is expected to be in after turn_on
+func onTarget(domain string) string {
+	if slices.Contains(OnDomains, domain) {
+		return "on"
+	}
+	return notOff
+}
+
+// setTarget records the state the last action is expected to result in
+func (h *Hass) setTarget(domain, name, state string, attributes map[string]float64) {
+	h.Result = HassResult{
+		EntityID:         fmt.Sprintf("%s.%s", domain, name),
+		TargetState:      state,
+		TargetAttributes: attributes,
+	}
+}
+
+// FetchState gets a single state directly from the hub, bypassing the cached states
+func (h *Hass) FetchState(domain, name string) (HassState, error) {
+	var state HassState
+	res, err := h.api("GET", fmt.Sprintf("/states/%s.%s", domain, name), nil)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(res, &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func targetReached(s HassState, state string, attributes map[string]float64) bool {
+	if not, ok := strings.CutPrefix(state, "!"); ok {
+		if s.State == not {
+			return false
+		}
+	} else if state != "" && s.State != state {
+		return false
+	}
+	for k, want := range attributes {
+		got, ok := toFloat(s.Attributes[k])
+		if !ok || math.Abs(got-want) > targetTolerance[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func describeState(state string, attributes map[string]any) string {
+	var parts []string
+	if not, ok := strings.CutPrefix(state, "!"); ok {
+		parts = append(parts, "not "+not)
+	} else if state != "" {
+		parts = append(parts, state)
+	}
+	var keys []string
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, attributes[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Wait blocks until the entity of the last action reached its target state
+// and attributes, or fails when timeout has passed
+func (h *Hass) Wait(timeout time.Duration) (HassResult, error) {
+	res, err := WaitFor(h.Result, timeout, h.FetchState)
+	if err == nil {
+		h.Result = res
+	}
+	return res, err
+}
+
+// WaitFor polls the state of target.EntityID with fetch until it reached
+// target.TargetState and target.TargetAttributes, or fails when timeout has passed
+func WaitFor(target HassResult, timeout time.Duration, fetch func(domain, name string) (HassState, error)) (HassResult, error) {
+	if target.EntityID == "" {
+		return target, fmt.Errorf("no action to wait for")
+	}
+	if target.TargetState == "" && len(target.TargetAttributes) == 0 {
+		return target, fmt.Errorf("cannot determine target state of %s", target.EntityID)
+	}
+
+	targetAttrs := map[string]any{}
+	for k, v := range target.TargetAttributes {
+		targetAttrs[k] = v
+	}
+	domain, name := splitDomainAndName(target.EntityID)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		s, err := fetch(domain, name)
+		if err != nil {
+			return target, err
+		}
+		if targetReached(s, target.TargetState, target.TargetAttributes) {
+			target.State = s.State
+			if fn, ok := s.Attributes["friendly_name"].(string); ok {
+				target.Attributes.FriendlyName = fn
+			}
+			log.Debug().Caller().Msgf("%s reached %s", target.EntityID, describeState(target.TargetState, targetAttrs))
+			return target, nil
+		}
+
+		if time.Now().Add(waitInterval).After(deadline) {
+			current := map[string]any{}
+			for k := range target.TargetAttributes {
+				current[k] = s.Attributes[k]
+			}
+			return target, fmt.Errorf("timed out after %s waiting for %s to reach %s (current: %s)",
+				timeout, target.EntityID, describeState(target.TargetState, targetAttrs), describeState(s.State, current))
+		}
+		time.Sleep(waitInterval)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_Wait(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+
+	tests := map[string]struct {
+		action      func(h *Hass) error
+		wantEntity  string
+		wantState   string
+		wantTarget  string
+		wantAttribs map[string]float64
+	}{
+		"turn on": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.TurnOn("light.livingroom_other")
+				return err
+			},
+			wantEntity: "light.livingroom_other",
+			wantState:  "on",
+			wantTarget: "on",
+		},
+		"turn on with brightness": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.TurnLightOnCustom("light.bedroom_other", "20", "", 0, 0)
+				return err
+			},
+			wantEntity:  "light.bedroom_other",
+			wantState:   "on",
+			wantTarget:  "on",
+			wantAttribs: map[string]float64{"brightness": 52},
+		},
+		"turn on media player": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.TurnOn("media_player.player2")
+				return err
+			},
+			wantEntity: "media_player.player2",
+			wantState:  "on",
+			wantTarget: notOff,
+		},
+		"volume": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.VolumeSet("player1", 42)
+				return err
+			},
+			wantEntity:  "media_player.player1",
+			wantState:   "on",
+			wantAttribs: map[string]float64{"volume_level": 0.42},
+		},
+		"cover position": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.CoverPosition("kitchen_blinds", "30", false)
+				return err
+			},
+			wantEntity:  "cover.kitchen_blinds",
+			wantState:   "open",
+			wantAttribs: map[string]float64{"current_position": 30},
+		},
+		"fan speed": {
+			action: func(h *Hass) error {
+				_, _, _, _, err := h.FanPercentage("attic_fan", "+")
+				return err
+			},
+			wantEntity:  "fan.attic_fan",
+			wantState:   "on",
+			wantTarget:  "on",
+			wantAttribs: map[string]float64{"percentage": 33},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Hass{
+				APIURL: ms.URL,
+				Token:  "test_token",
+			}
+			if err := tt.action(h); err != nil {
+				t.Fatalf("Error running action: %v", err)
+			}
+			res, err := h.Wait(2 * time.Second)
+			if err != nil {
+				t.Fatalf("Error waiting: %v", err)
+			}
+			if res.EntityID != tt.wantEntity {
+				t.Errorf("got %s, want %s", res.EntityID, tt.wantEntity)
+			}
+			if res.State != tt.wantState {
+				t.Errorf("got state %s, want %s", res.State, tt.wantState)
+			}
+			if res.TargetState != tt.wantTarget {
+				t.Errorf("got target state %s, want %s", res.TargetState, tt.wantTarget)
+			}
+			for k, v := range tt.wantAttribs {
+				if res.TargetAttributes[k] != v {
+					t.Errorf("got target %s %v, want %v", k, res.TargetAttributes[k], v)
+				}
+			}
+		})
+	}
+}
+
+func Test_Wait_Toggle(t *testing.T) {
+	ms := hctltest.MockServer(t)
+	defer ms.Close()
+	h := &Hass{
+		APIURL: ms.URL,
+		Token:  "test_token",
+	}
+	// bedroom_main is on in testdata
+	if _, _, _, _, err := h.Toggle("light.bedroom_main"); err != nil {
+		t.Fatalf("Error toggling: %v", err)
+	}
+	res, err := h.Wait(2 * time.Second)
+	if err != nil {
+		t.Fatalf("Error waiting: %v", err)
+	}
+	if res.State != "off" || res.TargetState != "off" {
+		t.Errorf("got state %s (target %s), want off", res.State, res.TargetState)
+	}
+	if res.Attributes.FriendlyName != "Bedroom Main" {
+		t.Errorf("got %s, want Bedroom Main", res.Attributes.FriendlyName)
+	}
+}
+
+func Test_Wait_Timeout(t *testing.T) {
+	ms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"entity_id": "light.stuck", "state": "off", "attributes": {"brightness": null}}`)); err != nil {
+			t.Errorf("Error writing data: %v", err)
+		}
+	}))
+	defer ms.Close()
+
+	h := &Hass{
+		APIURL: ms.URL,
+		Token:  "test_token",
+	}
+	h.setTarget("light", "stuck", "on", map[string]float64{"brightness": 128})
+
+	_, err := h.Wait(300 * time.Millisecond)
+	want := "timed out after 300ms waiting for light.stuck to reach on, brightness=128 (current: off, brightness=<nil>)"
+	if err == nil {
+		t.Fatalf("expected error %q, got nil", want)
+	}
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err.Error(), want)
+	}
+}
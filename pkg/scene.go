@@ -0,0 +1,115 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/scene"
+)
+
+func (h *Hctl) sceneStore() (*scene.Store, error) {
+	dir, err := scene.DefaultDir(h.cfg.Viper.ConfigFileUsed())
+	if err != nil {
+		return nil, err
+	}
+	return &scene.Store{Dir: dir}, nil
+}
+
+// ActivateScene turns on a scene of the hub, with a transition in seconds if > 0
+// Returns the entity id of the scene
+func (h *Hctl) ActivateScene(name string, transition float64) (string, []rest.HassResult, error) {
+	var data map[string]any
+	if transition > 0 {
+		data = map[string]any{"transition": transition}
+	}
+	return h.callInDomain("scene", name, "turn_on", data)
+}
+
+// SaveScene saves the current state of entities (resolved like in any other action)
+// as local scene, replacing an existing scene with the same name
+func (h *Hctl) SaveScene(name string, entities []string) (*scene.Scene, error) {
+	c, err := h.GetHass("scene")
+	if err != nil {
+		return nil, err
+	}
+	if err := scene.ValidateName(name); err != nil {
+		return nil, err
+	}
+	var states []rest.HassState
+	for _, e := range entities {
+		domain, obj, err := c.ResolveEntityInDomains(e, scene.Domains()...)
+		if err != nil {
+			return nil, err
+		}
+		state, err := c.FetchState(domain, obj)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	s, err := scene.New(name, states, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	store, err := h.sceneStore()
+	if err != nil {
+		return nil, err
+	}
+	return s, store.Save(s)
+}
+
+// LoadScene reads a local scene
+func (h *Hctl) LoadScene(name string) (*scene.Scene, error) {
+	store, err := h.sceneStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Load(name)
+}
+
+// ListScenes returns the names of all local scenes
+func (h *Hctl) ListScenes() ([]string, error) {
+	store, err := h.sceneStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List()
+}
+
+// RestoreEntity replays the saved state of an entity, with a transition in seconds for lights if > 0
+// Returns the states changed by the calls
+func (h *Hctl) RestoreEntity(e scene.Entity, transition float64) ([]rest.HassResult, error) {
+	c, err := h.GetHass("scene")
+	if err != nil {
+		return nil, err
+	}
+	calls := e.Calls(transition)
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("can't restore %s, supported domains are %s", e.EntityID, strings.Join(scene.Domains(), ", "))
+	}
+	var results []rest.HassResult
+	for _, call := range calls {
+		r, err := c.CallService(call.Domain, call.Service, []string{call.EntityID}, call.Data)
+		if err != nil {
+			return results, fmt.Errorf("%s: %v", e.EntityID, err)
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
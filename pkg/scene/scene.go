@@ -0,0 +1,319 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scene captures the state of entities into local scene files and
+// turns them back into the service calls restoring that state
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// attributes per domain that are saved and restored along with the state
+var attributes = map[string][]string{
+	"light":         {"brightness", "color_mode", "color_temp_kelvin", "hs_color", "xy_color", "rgb_color", "rgbw_color", "rgbww_color"},
+	"switch":        {},
+	"input_boolean": {},
+	"siren":         {},
+	"fan":           {"percentage", "preset_mode", "oscillating", "direction"},
+	"media_player":  {"volume_level", "is_volume_muted", "source"},
+	"climate":       {"temperature", "preset_mode"},
+	"cover":         {"current_position", "current_tilt_position"},
+}
+
+// light attribute to restore for each color mode
+var colorModes = map[string]string{
+	"color_temp": "color_temp_kelvin",
+	"hs":         "hs_color",
+	"xy":         "xy_color",
+	"rgb":        "rgb_color",
+	"rgbw":       "rgbw_color",
+	"rgbww":      "rgbww_color",
+}
+
+// states the hub reports for entities it can't reach, they can't be restored
+var noState = []string{"unavailable", "unknown"}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
+// Scene is a set of entity states saved to a local file
+type Scene struct {
+	Name     string   `yaml:"name" json:"name"`
+	Created  string   `yaml:"created" json:"created"`
+	Entities []Entity `yaml:"entities" json:"entities"`
+	// Skipped are the entities left out by New, as they had no state to save
+	Skipped []Entity `yaml:"-" json:"-"`
+}
+
+// Entity is the saved state of a single entity
+type Entity struct {
+	EntityID   string         `yaml:"entity_id" json:"entity_id"`
+	State      string         `yaml:"state" json:"state"`
+	Attributes map[string]any `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+// Call is a service call restoring (part of) the state of an entity
+type Call struct {
+	Domain   string
+	Service  string
+	EntityID string
+	Data     map[string]any
+}
+
+// Domains returns all domains that can be saved in a scene
+func Domains() []string {
+	var domains []string
+	for d := range attributes {
+		domains = append(domains, d)
+	}
+	slices.Sort(domains)
+	return domains
+}
+
+// New creates a scene from the current states, only keeping the attributes needed to restore them
+// Entities that are unavailable or unknown are left out and kept in Skipped
+func New(name string, states []rest.HassState, now time.Time) (*Scene, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("a scene needs at least one entity")
+	}
+	s := &Scene{Name: name, Created: now.Format(time.RFC3339)}
+	for _, state := range states {
+		domain, _, _ := strings.Cut(state.EntityID, ".")
+		attrs, ok := attributes[domain]
+		if !ok {
+			return nil, fmt.Errorf("can't save %s, supported domains are %s", state.EntityID, strings.Join(Domains(), ", "))
+		}
+		e := Entity{EntityID: state.EntityID, State: state.State}
+		if !e.HasState() {
+			s.Skipped = append(s.Skipped, e)
+			continue
+		}
+		for _, a := range attrs {
+			if v, ok := state.Attributes[a]; ok && v != nil {
+				if e.Attributes == nil {
+					e.Attributes = map[string]any{}
+				}
+				e.Attributes[a] = v
+			}
+		}
+		s.Entities = append(s.Entities, e)
+	}
+	if len(s.Entities) == 0 {
+		return nil, fmt.Errorf("none of the entities has a state to save")
+	}
+	return s, nil
+}
+
+// ValidateName makes sure name can be used as file name
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid scene name %q, use letters, digits, '_', '-' and '.'", name)
+	}
+	return nil
+}
+
+// Calls returns the service calls to restore all entities of the scene
+// transition (seconds) is used for lights, if > 0
+func (s *Scene) Calls(transition float64) []Call {
+	var calls []Call
+	for _, e := range s.Entities {
+		calls = append(calls, e.Calls(transition)...)
+	}
+	return calls
+}
+
+// HasState reports whether the entity was reachable when saved, i.e. not unavailable or unknown
+func (e Entity) HasState() bool {
+	return !slices.Contains(noState, e.State)
+}
+
+// Calls returns the service calls to restore the entity, nil if its state can't be restored
+func (e Entity) Calls(transition float64) []Call {
+	if !e.HasState() {
+		return nil
+	}
+	domain, _, _ := strings.Cut(e.EntityID, ".")
+	call := func(service string, data map[string]any) Call {
+		return Call{Domain: domain, Service: service, EntityID: e.EntityID, Data: data}
+	}
+	a := e.Attributes
+
+	switch domain {
+	case "light":
+		data := map[string]any{}
+		if transition > 0 {
+			data["transition"] = transition
+		}
+		if e.State != "on" {
+			return []Call{call("turn_off", data)}
+		}
+		if v, ok := a["brightness"]; ok {
+			data["brightness"] = v
+		}
+		if mode, ok := a["color_mode"].(string); ok {
+			if attr, ok := colorModes[mode]; ok && a[attr] != nil {
+				data[attr] = a[attr]
+			}
+		}
+		return []Call{call("turn_on", data)}
+	case "fan":
+		if e.State != "on" {
+			return []Call{call("turn_off", nil)}
+		}
+		data := map[string]any{}
+		if v, ok := a["preset_mode"]; ok {
+			data["preset_mode"] = v
+		} else if v, ok := a["percentage"]; ok {
+			data["percentage"] = v
+		}
+		calls := []Call{call("turn_on", data)}
+		if v, ok := a["oscillating"]; ok {
+			calls = append(calls, call("oscillate", map[string]any{"oscillating": v}))
+		}
+		if v, ok := a["direction"]; ok {
+			calls = append(calls, call("set_direction", map[string]any{"direction": v}))
+		}
+		return calls
+	case "media_player":
+		if e.State == "off" || e.State == "standby" {
+			return []Call{call("turn_off", nil)}
+		}
+		calls := []Call{call("turn_on", nil)}
+		if v, ok := a["volume_level"]; ok {
+			calls = append(calls, call("volume_set", map[string]any{"volume_level": v}))
+		}
+		if v, ok := a["is_volume_muted"]; ok {
+			calls = append(calls, call("volume_mute", map[string]any{"is_volume_muted": v}))
+		}
+		if v, ok := a["source"]; ok {
+			calls = append(calls, call("select_source", map[string]any{"source": v}))
+		}
+		return calls
+	case "climate":
+		calls := []Call{call("set_hvac_mode", map[string]any{"hvac_mode": e.State})}
+		if e.State == "off" {
+			return calls
+		}
+		if v, ok := a["temperature"]; ok {
+			calls = append(calls, call("set_temperature", map[string]any{"temperature": v}))
+		}
+		if v, ok := a["preset_mode"]; ok {
+			calls = append(calls, call("set_preset_mode", map[string]any{"preset_mode": v}))
+		}
+		return calls
+	case "cover":
+		var calls []Call
+		if v, ok := a["current_position"]; ok {
+			calls = append(calls, call("set_cover_position", map[string]any{"position": v}))
+		} else if e.State == "open" || e.State == "opening" {
+			calls = append(calls, call("open_cover", nil))
+		} else {
+			calls = append(calls, call("close_cover", nil))
+		}
+		if v, ok := a["current_tilt_position"]; ok {
+			calls = append(calls, call("set_cover_tilt_position", map[string]any{"tilt_position": v}))
+		}
+		return calls
+	case "switch", "input_boolean", "siren":
+		if e.State == "on" {
+			return []Call{call("turn_on", nil)}
+		}
+		return []Call{call("turn_off", nil)}
+	}
+	return nil
+}
+
+// Store keeps scenes as YAML files in Dir
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns the directory for scenes next to the config file in use,
+// or ~/.config/hctl/scenes if there is none
+func DefaultDir(configFile string) (string, error) {
+	if configFile != "" {
+		return filepath.Join(filepath.Dir(configFile), "scenes"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "hctl", "scenes"), nil
+}
+
+func (st *Store) path(name string) string {
+	return filepath.Join(st.Dir, name+".yaml")
+}
+
+// Save writes the scene, replacing an existing scene with the same name
+func (st *Store) Save(s *Scene) error {
+	if err := ValidateName(s.Name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(st.Dir, 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(st.path(s.Name), data, 0600)
+}
+
+// Load reads the scene with the given name
+func (st *Store) Load(name string) (*Scene, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(st.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("scene %s not found in %s", name, st.Dir)
+	} else if err != nil {
+		return nil, err
+	}
+	s := &Scene{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("could not read scene %s: %v", name, err)
+	}
+	s.Name = name
+	return s, nil
+}
+
+// List returns the names of all saved scenes
+func (st *Store) List() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(st.Dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".yaml"))
+	}
+	return names, nil
+}
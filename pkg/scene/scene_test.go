@@ -0,0 +1,139 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scene
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+var testStates = []rest.HassState{
+	{EntityID: "light.desk", State: "on", Attributes: map[string]any{
+		"brightness": 180.0, "color_mode": "color_temp", "color_temp_kelvin": 4000.0, "hs_color": []any{30.0, 20.0},
+		"effect": nil, "friendly_name": "Desk",
+	}},
+	{EntityID: "light.ceiling", State: "off", Attributes: map[string]any{"brightness": nil, "friendly_name": "Ceiling"}},
+	{EntityID: "media_player.radio", State: "playing", Attributes: map[string]any{"volume_level": 0.2, "source": "FM"}},
+	{EntityID: "climate.office", State: "heat", Attributes: map[string]any{"temperature": 21.5, "current_temperature": 20.1}},
+}
+
+func Test_New(t *testing.T) {
+	now := time.Date(2024, 10, 9, 8, 0, 0, 0, time.UTC)
+	s, err := New("wfh", testStates, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Created != "2024-10-09T08:00:00Z" || len(s.Entities) != 4 {
+		t.Fatalf("got %+v", s)
+	}
+	want := map[string]any{"brightness": 180.0, "color_mode": "color_temp", "color_temp_kelvin": 4000.0, "hs_color": []any{30.0, 20.0}}
+	if !reflect.DeepEqual(s.Entities[0].Attributes, want) {
+		t.Errorf("got attributes %v, want %v", s.Entities[0].Attributes, want)
+	}
+	if s.Entities[1].Attributes != nil {
+		t.Errorf("got attributes %v for light that is off, want none", s.Entities[1].Attributes)
+	}
+
+	if _, err := New("wfh", []rest.HassState{{EntityID: "sensor.temp", State: "20"}}, now); err == nil {
+		t.Error("expected error for unsupported domain")
+	}
+	if _, err := New("../wfh", testStates, now); err == nil {
+		t.Error("expected error for invalid name")
+	}
+	if _, err := New("wfh", nil, now); err == nil {
+		t.Error("expected error for scene without entities")
+	}
+
+	states := append([]rest.HassState{
+		{EntityID: "climate.attic", State: "unavailable"},
+		{EntityID: "switch.porch", State: "unknown"},
+	}, testStates...)
+	s, err = New("wfh", states, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Entities) != 4 || len(s.Skipped) != 2 || s.Skipped[0].EntityID != "climate.attic" || s.Skipped[1].EntityID != "switch.porch" {
+		t.Errorf("got entities %+v, skipped %+v, want unavailable and unknown skipped", s.Entities, s.Skipped)
+	}
+	if _, err := New("wfh", states[:2], now); err == nil {
+		t.Error("expected error for scene without any entity to save")
+	}
+}
+
+func Test_Calls(t *testing.T) {
+	s, err := New("wfh", testStates, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Call{
+		{"light", "turn_on", "light.desk", map[string]any{"brightness": 180.0, "color_temp_kelvin": 4000.0, "transition": 2.0}},
+		{"light", "turn_off", "light.ceiling", map[string]any{"transition": 2.0}},
+		{"media_player", "turn_on", "media_player.radio", nil},
+		{"media_player", "volume_set", "media_player.radio", map[string]any{"volume_level": 0.2}},
+		{"media_player", "select_source", "media_player.radio", map[string]any{"source": "FM"}},
+		{"climate", "set_hvac_mode", "climate.office", map[string]any{"hvac_mode": "heat"}},
+		{"climate", "set_temperature", "climate.office", map[string]any{"temperature": 21.5}},
+	}
+	if got := s.Calls(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := (Entity{EntityID: "sensor.temp", State: "20"}).Calls(0); got != nil {
+		t.Errorf("got %+v for unsupported domain, want nil", got)
+	}
+	for _, e := range []Entity{{EntityID: "climate.attic", State: "unavailable"}, {EntityID: "light.porch", State: "unknown"}} {
+		if got := e.Calls(0); got != nil {
+			t.Errorf("got %+v for %s %s, want nil", got, e.EntityID, e.State)
+		}
+	}
+}
+
+func Test_Store(t *testing.T) {
+	st := &Store{Dir: t.TempDir()}
+	s, err := New("wfh", testStates, time.Date(2024, 10, 9, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Save(s); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := st.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"wfh"}) {
+		t.Errorf("got %v, want [wfh]", names)
+	}
+
+	loaded, err := st.Load("wfh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entities) != 4 || loaded.Entities[2].State != "playing" || loaded.Entities[2].Attributes["source"] != "FM" {
+		t.Errorf("got %+v", loaded)
+	}
+	// calls of a loaded scene have to match the saved one
+	if got, want := len(loaded.Calls(0)), len(s.Calls(0)); got != want {
+		t.Errorf("got %d calls after loading, want %d", got, want)
+	}
+
+	if _, err := st.Load("missing"); err == nil {
+		t.Error("expected error for missing scene")
+	}
+}
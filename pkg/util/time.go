@@ -0,0 +1,80 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts accepted by ParseTime, besides durations
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// ParseTime parses s either as duration before now (e.g. 24h, 90m, 7d), as duration after now
+// with a leading + (e.g. +2d), or as timestamp in local time (e.g. 2024-10-09 18:00, 2024-10-09)
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if after, ok := strings.CutPrefix(s, "+"); ok {
+		d, err := parseDuration(after)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration: %s", s)
+		}
+		return now.Add(d), nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use a duration like 24h or a timestamp like 2006-01-02 15:04)", s)
+}
+
+// ParseFutureTime is ParseTime for times that are usually ahead, e.g. the end of a calendar
+// period: durations are after now (e.g. 7d or +7d), or before now with a leading - (e.g. -1d)
+func ParseFutureTime(s string, now time.Time) (time.Time, error) {
+	if before, ok := strings.CutPrefix(s, "-"); ok {
+		d, err := parseDuration(before)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration: %s", s)
+		}
+		return now.Add(-d), nil
+	}
+	if d, err := parseDuration(strings.TrimPrefix(s, "+")); err == nil {
+		return now.Add(d), nil
+	}
+	return ParseTime(s, now)
+}
+
+// parseDuration is time.ParseDuration, but also takes whole days (e.g. 7d)
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
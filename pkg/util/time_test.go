@@ -0,0 +1,94 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ParseTime(t *testing.T) {
+	now := time.Date(2024, 10, 9, 12, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		"duration":      {in: "24h", want: now.Add(-24 * time.Hour)},
+		"minutes":       {in: "90m", want: now.Add(-90 * time.Minute)},
+		"days":          {in: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		"ahead":         {in: "+2d", want: now.Add(48 * time.Hour)},
+		"ahead hours":   {in: "+90m", want: now.Add(90 * time.Minute)},
+		"invalid ahead": {in: "+tomorrow", wantErr: true},
+		"rfc3339":       {in: "2024-10-08T22:00:00Z", want: time.Date(2024, 10, 8, 22, 0, 0, 0, time.UTC)},
+		"date and time": {in: "2024-10-08 22:30", want: time.Date(2024, 10, 8, 22, 30, 0, 0, time.Local)},
+		"date":          {in: "2024-10-08", want: time.Date(2024, 10, 8, 0, 0, 0, 0, time.Local)},
+		"invalid":       {in: "last night", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseTime(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseFutureTime(t *testing.T) {
+	now := time.Date(2024, 10, 9, 12, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		"duration":       {in: "2h", want: now.Add(2 * time.Hour)},
+		"days":           {in: "7d", want: now.Add(7 * 24 * time.Hour)},
+		"ahead":          {in: "+30d", want: now.Add(30 * 24 * time.Hour)},
+		"before":         {in: "-1d", want: now.Add(-24 * time.Hour)},
+		"invalid before": {in: "-yesterday", wantErr: true},
+		"invalid ahead":  {in: "+tomorrow", wantErr: true},
+		"date and time":  {in: "2024-10-08 22:30", want: time.Date(2024, 10, 8, 22, 30, 0, 0, time.Local)},
+		"invalid":        {in: "next week", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseFutureTime(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
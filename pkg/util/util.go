@@ -57,17 +57,26 @@ func IsURL(url string) bool {
 }
 
 func GetLocalIP() string {
+	ip, err := LocalIP()
+	if err != nil {
+		log.Fatal().Msgf("Error getting local IP: %v", err)
+	}
+	return ip
+}
+
+// LocalIP returns the IP of the interface used for outgoing traffic
+func LocalIP() (string, error) {
 	log.Debug().Caller().Msg("Getting local IP")
 	conn, err := net.Dial("udp", "1.1.1.1:53")
 	if err != nil {
-		log.Fatal().Msgf("Error getting local IP: %v", err)
+		return "", err
 	}
 	defer conn.Close()
 
 	localAddress := conn.LocalAddr().(*net.UDPAddr)
 
 	log.Debug().Caller().Msgf("Using local IP: %s", localAddress.IP)
-	return localAddress.IP.String()
+	return localAddress.IP.String(), nil
 }
 
 func MakeRange(mini, maxi int) []int {
@@ -0,0 +1,238 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	o "github.com/xx4h/hctl/pkg/output"
+	"github.com/xx4h/hctl/pkg/rest"
+	"github.com/xx4h/hctl/pkg/ws"
+)
+
+// WatchFilter selects the state changes printed by Watch
+// An empty filter matches all entities
+type WatchFilter struct {
+	entities []string
+	patterns []string
+	domains  []string
+}
+
+type AttributeChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+type StateChange struct {
+	TimeFired  time.Time                  `json:"time_fired"`
+	EntityID   string                     `json:"entity_id"`
+	OldState   string                     `json:"old_state"`
+	NewState   string                     `json:"new_state"`
+	Attributes map[string]AttributeChange `json:"changed_attributes"`
+}
+
+func isGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// NewWatchFilter creates a filter from entity names or glob patterns and domains
+// Names are resolved like in any other action (short names, device_map and fuzzy matching)
+func (h *Hctl) NewWatchFilter(names []string, domains []string) (*WatchFilter, error) {
+	c, err := h.GetHass("watch")
+	if err != nil {
+		return nil, err
+	}
+	return newWatchFilter(c, names, domains)
+}
+
+func newWatchFilter(c *rest.Hass, names []string, domains []string) (*WatchFilter, error) {
+	f := &WatchFilter{domains: domains}
+	for _, name := range names {
+		if isGlob(name) {
+			if _, err := path.Match(name, ""); err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %s: %v", name, err)
+			}
+			f.patterns = append(f.patterns, name)
+			continue
+		}
+		domain, obj, err := c.ResolveEntity(name)
+		if err != nil {
+			return nil, err
+		}
+		f.entities = append(f.entities, fmt.Sprintf("%s.%s", domain, obj))
+	}
+	return f, nil
+}
+
+func (f *WatchFilter) Match(entityID string) bool {
+	domain, name, _ := strings.Cut(entityID, ".")
+	if len(f.domains) > 0 && !slices.Contains(f.domains, domain) {
+		return false
+	}
+	if len(f.entities) == 0 && len(f.patterns) == 0 {
+		return true
+	}
+	if slices.Contains(f.entities, entityID) {
+		return true
+	}
+	for _, p := range f.patterns {
+		// patterns without domain match the name only
+		subject := entityID
+		if !strings.Contains(p, ".") {
+			subject = name
+		}
+		if ok, _ := path.Match(p, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func stateString(s *rest.HassState) string {
+	if s == nil {
+		return ""
+	}
+	return s.State
+}
+
+func attributesOf(s *rest.HassState) map[string]any {
+	if s == nil {
+		return map[string]any{}
+	}
+	return s.Attributes
+}
+
+func NewStateChange(e ws.Event) (*StateChange, error) {
+	var d ws.StateChangedData
+	if err := json.Unmarshal(e.Data, &d); err != nil {
+		return nil, err
+	}
+
+	oldAttrs := attributesOf(d.OldState)
+	newAttrs := attributesOf(d.NewState)
+	changed := map[string]AttributeChange{}
+	for k, v := range newAttrs {
+		if o, ok := oldAttrs[k]; !ok || !reflect.DeepEqual(o, v) {
+			changed[k] = AttributeChange{Old: oldAttrs[k], New: v}
+		}
+	}
+	for k, v := range oldAttrs {
+		if _, ok := newAttrs[k]; !ok {
+			changed[k] = AttributeChange{Old: v}
+		}
+	}
+
+	return &StateChange{
+		TimeFired:  e.TimeFired,
+		EntityID:   d.EntityID,
+		OldState:   stateString(d.OldState),
+		NewState:   stateString(d.NewState),
+		Attributes: changed,
+	}, nil
+}
+
+func formatValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func (s *StateChange) String() string {
+	oldState, newState := s.OldState, s.NewState
+	if oldState == "" {
+		oldState = "(none)"
+	}
+	if newState == "" {
+		newState = "(removed)"
+	}
+
+	var keys []string
+	for k := range s.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var attrs []string
+	for _, k := range keys {
+		attrs = append(attrs, fmt.Sprintf("%s: %s → %s", k, formatValue(s.Attributes[k].Old), formatValue(s.Attributes[k].New)))
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s  %-40s %s → %s  %s", s.TimeFired.Local().Format(time.DateTime), s.EntityID, oldState, newState, strings.Join(attrs, ", ")))
+}
+
+// Watch prints state changes matching filter as they happen
+// It returns when ctx is done, count changes have been printed (if count > 0)
+// or the connection to the hub is lost
+func (h *Hctl) Watch(ctx context.Context, out io.Writer, filter *WatchFilter, count int) error {
+	if err := h.requireHass("watch"); err != nil {
+		return err
+	}
+	c := h.GetWebSocket()
+	if err := c.Connect(); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	sub, err := c.SubscribeEvents("state_changed")
+	if err != nil {
+		return err
+	}
+
+	var printed int
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				if err := c.Err(); err != nil {
+					return fmt.Errorf("lost connection to hub: %w", err)
+				}
+				return nil
+			}
+			change, err := NewStateChange(e)
+			if err != nil {
+				log.Debug().Caller().Msgf("Could not parse state_changed event: %+v", err)
+				continue
+			}
+			if !filter.Match(change.EntityID) {
+				continue
+			}
+			if o.Structured() {
+				if err := o.FprintRecord(out, change); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(out, change.String())
+			}
+			printed++
+			if count > 0 && printed >= count {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/xx4h/hctl/pkg/rest"
+)
+
+// Subscription receives events for a single subscribe_events command
+type Subscription struct {
+	ID int
+
+	client *Client
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+type StateChangedData struct {
+	EntityID string          `json:"entity_id"`
+	OldState *rest.HassState `json:"old_state"`
+	NewState *rest.HassState `json:"new_state"`
+}
+
+// Events returns the channel events are delivered on. It is closed when
+// the connection is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Unsubscribe stops event delivery for this subscription
+func (s *Subscription) Unsubscribe() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		s.client.mu.Lock()
+		_, ok := s.client.subs[s.ID]
+		delete(s.client.subs, s.ID)
+		s.client.mu.Unlock()
+		if ok {
+			_, err = s.client.Command("unsubscribe_events", map[string]any{"subscription": s.ID})
+		}
+	})
+	return err
+}
+
+// SubscribeEvents subscribes to events of the given type, or all events
+// if eventType is empty
+func (c *Client) SubscribeEvents(eventType string) (*Subscription, error) {
+	sub := &Subscription{
+		client: c,
+		events: make(chan Event, 64),
+		done:   make(chan struct{}),
+	}
+
+	payload := map[string]any{}
+	if eventType != "" {
+		payload["event_type"] = eventType
+	}
+
+	ch, err := c.send("subscribe_events", payload, sub)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.wait(ch); err != nil {
+		c.mu.Lock()
+		delete(c.subs, sub.ID)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetStates returns all states via the get_states command
+func (c *Client) GetStates() ([]rest.HassState, error) {
+	res, err := c.Command("get_states", nil)
+	if err != nil {
+		return nil, err
+	}
+	states := []rest.HassState{}
+	if err := json.Unmarshal(res, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// CallService calls domain.service with the given service data and target
+// (e.g. {"entity_id": "light.kitchen"}). The service response is returned
+// if the hub provides one.
+func (c *Client) CallService(domain string, service string, data map[string]any, target map[string]any) (json.RawMessage, error) {
+	payload := map[string]any{
+		"domain":  domain,
+		"service": service,
+	}
+	if data != nil {
+		payload["service_data"] = data
+	}
+	if target != nil {
+		payload["target"] = target
+	}
+
+	res, err := c.Command("call_service", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Response json.RawMessage `json:"response"`
+	}
+	if err := json.Unmarshal(res, &r); err != nil {
+		return nil, err
+	}
+	return r.Response, nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// Client is a Home Assistant WebSocket API client
+type Client struct {
+	APIURL string
+	Token  string
+	Dialer *websocket.Dialer
+
+	HAVersion string
+
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	lastID  int
+	pending map[int]chan Message
+	subs    map[int]*Subscription
+	closing bool
+	closed  bool
+	err     error
+}
+
+// Message is a single message as sent or received over the WebSocket API
+type Message struct {
+	ID          int             `json:"id,omitempty"`
+	Type        string          `json:"type"`
+	Success     bool            `json:"success,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *MessageError   `json:"error,omitempty"`
+	Event       json.RawMessage `json:"event,omitempty"`
+	HAVersion   string          `json:"ha_version,omitempty"`
+	Description string          `json:"message,omitempty"`
+}
+
+type MessageError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *MessageError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Event as delivered to subscriptions
+type Event struct {
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+	Origin    string          `json:"origin"`
+	TimeFired time.Time       `json:"time_fired"`
+}
+
+func New(apiURL string, token string) *Client {
+	return &Client{APIURL: apiURL, Token: token, Dialer: websocket.DefaultDialer}
+}
+
+// URL returns the WebSocket endpoint for the given REST API URL,
+// e.g. https://hass.example.com/api -> wss://hass.example.com/api/websocket
+func URL(apiURL string) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("unsupported URL scheme for websocket: %s", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/websocket"
+	return u.String(), nil
+}
+
+func (c *Client) preflight() error {
+	if c.APIURL == "" {
+		return errors.New("no Hub URL found: Run `hctl init` or manually create config")
+	}
+	if c.Token == "" {
+		return errors.New("no Hub Token found: Run `hctl init` or manually create config")
+	}
+	return nil
+}
+
+// Connect dials the WebSocket API, runs the auth handshake and starts
+// dispatching incoming messages
+func (c *Client) Connect() error {
+	if err := c.preflight(); err != nil {
+		return err
+	}
+
+	wsURL, err := URL(c.APIURL)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("Connecting to WebSocket %s", wsURL)
+	conn, res, err := c.Dialer.Dial(wsURL, nil)
+	if err != nil {
+		if res != nil {
+			return fmt.Errorf("websocket handshake failed (%d): %w", res.StatusCode, err)
+		}
+		return err
+	}
+	if res != nil && res.Body != nil {
+		res.Body.Close()
+	}
+	c.conn = conn
+
+	if err := c.auth(); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.pending = make(map[int]chan Message)
+	c.subs = make(map[int]*Subscription)
+	go c.readLoop()
+	return nil
+}
+
+func (c *Client) auth() error {
+	var msg Message
+	if err := c.conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	if msg.Type != "auth_required" {
+		return fmt.Errorf("unexpected websocket message: %s", msg.Type)
+	}
+
+	if err := c.write(map[string]any{"type": "auth", "access_token": c.Token}); err != nil {
+		return err
+	}
+
+	if err := c.conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	switch msg.Type {
+	case "auth_ok":
+		c.HAVersion = msg.HAVersion
+		log.Debug().Caller().Msgf("WebSocket authenticated, ha_version %s", msg.HAVersion)
+		return nil
+	case "auth_invalid":
+		return fmt.Errorf("authentication failed: %s", msg.Description)
+	default:
+		return fmt.Errorf("unexpected websocket message: %s", msg.Type)
+	}
+}
+
+func (c *Client) write(v any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+func (c *Client) readLoop() {
+	for {
+		var msg Message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			c.shutdown(err)
+			return
+		}
+		log.Trace().Caller().Msgf("WebSocket message: %+v", msg)
+
+		switch msg.Type {
+		case "result":
+			c.mu.Lock()
+			ch, ok := c.pending[msg.ID]
+			delete(c.pending, msg.ID)
+			c.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+		case "event":
+			c.mu.Lock()
+			sub, ok := c.subs[msg.ID]
+			c.mu.Unlock()
+			if !ok {
+				continue
+			}
+			var e Event
+			if err := json.Unmarshal(msg.Event, &e); err != nil {
+				log.Debug().Caller().Msgf("Could not unmarshal event: %+v", err)
+				continue
+			}
+			select {
+			case sub.events <- e:
+			case <-sub.done:
+			}
+		}
+	}
+}
+
+// shutdown is only called by readLoop, so nobody is sending on the
+// channels we close here
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if !c.closing && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		c.err = err
+	}
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	for id, sub := range c.subs {
+		close(sub.events)
+		delete(c.subs, id)
+	}
+}
+
+// Err returns the error that terminated the connection, if any
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close closes the connection to the hub
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	err := c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+	if cerr := c.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// send registers a new message id, sends the message and returns
+// a channel receiving the result. If sub is given, it is registered for
+// events with the same id before the message is sent.
+func (c *Client) send(msgType string, payload map[string]any, sub *Subscription) (chan Message, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("websocket connection closed")
+	}
+	c.lastID++
+	id := c.lastID
+	ch := make(chan Message, 1)
+	c.pending[id] = ch
+	if sub != nil {
+		sub.ID = id
+		c.subs[id] = sub
+	}
+	c.mu.Unlock()
+
+	msg := map[string]any{}
+	for k, v := range payload {
+		msg[k] = v
+	}
+	msg["id"] = id
+	msg["type"] = msgType
+
+	log.Info().Msgf("Sending WebSocket command %s (id %d), Payload: %#v", msgType, id, payload)
+	if err := c.write(msg); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		delete(c.subs, id)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (c *Client) wait(ch chan Message) (Message, error) {
+	msg, ok := <-ch
+	if !ok {
+		if err := c.Err(); err != nil {
+			return msg, err
+		}
+		return msg, errors.New("websocket connection closed")
+	}
+	if !msg.Success {
+		if msg.Error != nil {
+			return msg, msg.Error
+		}
+		return msg, errors.New("websocket command failed")
+	}
+	return msg, nil
+}
+
+// Command sends a command of the given type and returns its result
+func (c *Client) Command(msgType string, payload map[string]any) (json.RawMessage, error) {
+	ch, err := c.send(msgType, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := c.wait(ch)
+	if err != nil {
+		return nil, err
+	}
+	return msg.Result, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Fabian `xx4h` Sylvester
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/xx4h/hctl/pkg/hctltest"
+)
+
+func Test_URL(t *testing.T) {
+	var tests = map[string]struct {
+		in      string
+		out     string
+		wantErr bool
+	}{
+		"https api url": {
+			"https://hass.example.com/api",
+			"wss://hass.example.com/api/websocket",
+			false,
+		},
+		"http api url with trailing slash": {
+			"http://127.0.0.1:8123/api/",
+			"ws://127.0.0.1:8123/api/websocket",
+			false,
+		},
+		"unsupported scheme": {
+			"ftp://hass.example.com/api",
+			"",
+			true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			u, err := URL(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if u != tt.out {
+				t.Errorf("got %s, want %s", u, tt.out)
+			}
+		})
+	}
+}
+
+func newTestingClient(t *testing.T, token string) *Client {
+	t.Helper()
+	ms := hctltest.MockServer(t)
+	t.Cleanup(ms.Close)
+	return New(ms.URL, token)
+}
+
+func Test_Connect(t *testing.T) {
+	c := newTestingClient(t, "test_token")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	defer c.Close()
+	if c.HAVersion != "2024.10.0" {
+		t.Errorf("got %s, want 2024.10.0", c.HAVersion)
+	}
+}
+
+func Test_Connect_InvalidToken(t *testing.T) {
+	c := newTestingClient(t, hctltest.InvalidToken)
+	err := c.Connect()
+	want := "authentication failed: Invalid access token or password"
+	if err == nil {
+		t.Fatalf("expected error %q, got nil", want)
+	}
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err.Error(), want)
+	}
+}
+
+func Test_GetStates(t *testing.T) {
+	c := newTestingClient(t, "test_token")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	defer c.Close()
+	s, err := c.GetStates()
+	if err != nil {
+		t.Errorf("Error getting states: %v", err)
+	}
+	if len(s) != 29 {
+		t.Errorf("got %d, want %d", len(s), 29)
+	}
+}
+
+func Test_CallService(t *testing.T) {
+	c := newTestingClient(t, "test_token")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.CallService("light", "turn_on", nil, map[string]any{"entity_id": "light.bedroom_main"}); err != nil {
+		t.Errorf("Error calling service: %v", err)
+	}
+
+	_, err := c.CallService("light", "speak", nil, nil)
+	want := "not_found: Service light.speak not found."
+	if err == nil {
+		t.Errorf("expected error %q, got nil", want)
+	} else if err.Error() != want {
+		t.Errorf("got error %q, want %q", err.Error(), want)
+	}
+}
+
+func Test_SubscribeEvents(t *testing.T) {
+	c := newTestingClient(t, "test_token")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	defer c.Close()
+
+	sub, err := c.SubscribeEvents("state_changed")
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < 3 {
+		select {
+		case e := <-sub.Events():
+			var d StateChangedData
+			if err := json.Unmarshal(e.Data, &d); err != nil {
+				t.Fatalf("Error unmarshal: %v", err)
+			}
+			got = append(got, d.EntityID)
+		case <-timeout:
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+
+	want := []string{"light.bedroom_main", "switch.livingroom_warp", "media_player.player1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %s, want %s", got[i], want[i])
+		}
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Errorf("Error unsubscribing: %v", err)
+	}
+}